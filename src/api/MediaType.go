@@ -0,0 +1,196 @@
+package api
+
+import (
+	"mime"
+	"sort"
+	"strings"
+)
+
+// MediaType is a parsed Content-Type value, such as
+// "application/problem+json; charset=utf-8".
+// Type & Subtype may be wildcards ("*") when the media type comes
+// from a spec content map, and Suffix holds the structured syntax
+// suffix ("json" in "application/vnd.api+json") when there is one.
+type MediaType struct {
+	Type    string
+	Subtype string
+	Suffix  string
+	Params  map[string]string
+}
+
+// ParseMediaType parses a Content-Type value into a MediaType instance.
+// Malformed parameters are ignored, so "application/json; charset" still
+// yields a usable "application/json" media type.
+func ParseMediaType(v string) MediaType {
+	mt := MediaType{
+		Params: map[string]string{},
+	}
+
+	essence, params, err := mime.ParseMediaType(v)
+	if err != nil {
+		essence = strings.ToLower(strings.TrimSpace(strings.Split(v, ";")[0]))
+	} else {
+		mt.Params = params
+	}
+
+	if essence == "*" {
+		essence = "*/*"
+	}
+
+	parts := strings.SplitN(essence, "/", 2)
+	mt.Type = parts[0]
+	if len(parts) > 1 {
+		mt.Subtype = parts[1]
+	}
+
+	if i := strings.LastIndex(mt.Subtype, "+"); i != -1 {
+		mt.Suffix = mt.Subtype[i+1:]
+	}
+
+	return mt
+}
+
+// Essence returns the media type without parameters, i.e. "type/subtype".
+func (mt MediaType) Essence() string {
+	return mt.Type + "/" + mt.Subtype
+}
+
+// IsWildcard tells whether the media type contains wildcards.
+func (mt MediaType) IsWildcard() bool {
+	return mt.Type == "*" || strings.HasPrefix(mt.Subtype, "*")
+}
+
+// IsJSON tells whether the media type denotes JSON-compatible content,
+// i.e. it's either application/json, text/json or has the +json suffix.
+func (mt MediaType) IsJSON() bool {
+	return mt.Subtype == "json" || mt.Suffix == "json"
+}
+
+// IsXML tells whether the media type denotes XML content,
+// i.e. it's either application/xml, text/xml or has the +xml suffix.
+func (mt MediaType) IsXML() bool {
+	return mt.Subtype == "xml" || mt.Suffix == "xml"
+}
+
+// IsNDJSON tells whether the media type denotes newline-delimited JSON,
+// where every line is a separate JSON document.
+func (mt MediaType) IsNDJSON() bool {
+	switch mt.Essence() {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return true
+	}
+
+	return false
+}
+
+// IsEventStream tells whether the media type denotes Server-Sent Events.
+func (mt MediaType) IsEventStream() bool {
+	return mt.Essence() == "text/event-stream"
+}
+
+// IsCSV tells whether the media type denotes comma-separated values.
+func (mt MediaType) IsCSV() bool {
+	return mt.Essence() == "text/csv"
+}
+
+// IsText tells whether the media type denotes textual content.
+func (mt MediaType) IsText() bool {
+	return mt.Type == "text"
+}
+
+// IsBinary tells whether the media type denotes opaque binary content,
+// such as application/octet-stream, images, audio & video.
+func (mt MediaType) IsBinary() bool {
+	switch mt.Type {
+	case "image", "audio", "video":
+		return true
+	}
+
+	return mt.Essence() == "application/octet-stream"
+}
+
+// Matches tells whether the actual media type satisfies mt, which is
+// treated as a pattern. Patterns may have wildcard types ("*/*"),
+// wildcard subtypes ("application/*") and wildcard subtypes with a suffix
+// ("application/*+json"). Parameters present in the pattern must be present
+// in the actual media type with the same values; charset values are
+// compared case-insensitively.
+func (mt MediaType) Matches(actual MediaType) bool {
+	if mt.Type != "*" && mt.Type != actual.Type {
+		return false
+	}
+
+	switch {
+	case mt.Subtype == "*":
+	case strings.HasPrefix(mt.Subtype, "*+"):
+		if mt.Suffix != actual.Suffix {
+			return false
+		}
+	case mt.Subtype != actual.Subtype:
+		return false
+	}
+
+	for pn, pv := range mt.Params {
+		av, ok := actual.Params[pn]
+		if !ok {
+			return false
+		}
+
+		if pn == "charset" {
+			if !strings.EqualFold(pv, av) {
+				return false
+			}
+		} else if pv != av {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Specificity ranks media type patterns so that the more specific ones
+// are preferred when several of them match the same media type.
+func (mt MediaType) Specificity() int {
+	switch {
+	case mt.Type == "*":
+		return 0
+	case mt.Subtype == "*":
+		return 1
+	case strings.HasPrefix(mt.Subtype, "*+"):
+		return 2
+	}
+
+	return 3 + len(mt.Params)
+}
+
+// MatchMediaType checks whether the CT value matches the pattern value.
+func MatchMediaType(pattern string, CT string) bool {
+	return ParseMediaType(pattern).Matches(ParseMediaType(CT))
+}
+
+// SelectMediaType picks the key from a list of media type patterns
+// (like keys of the OAS content map) that matches CT most specifically.
+// When CT is a wildcard itself, keys that the CT pattern matches
+// are considered as well. Returns false when nothing matches.
+func SelectMediaType(keys []string, CT string) (string, bool) {
+	ct := ParseMediaType(CT)
+
+	// Sorting to get deterministic results from maps' keys.
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+
+	best := ""
+	bestSpecificity := -1
+
+	for _, key := range sorted {
+		kmt := ParseMediaType(key)
+		if kmt.Matches(ct) || (ct.IsWildcard() && ct.Matches(kmt)) {
+			if s := kmt.Specificity(); s > bestSpecificity {
+				best = key
+				bestSpecificity = s
+			}
+		}
+	}
+
+	return best, bestSpecificity != -1
+}
@@ -0,0 +1,112 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+)
+
+func Test_ParseMediaType(T *testing.T) {
+	T.Run("Suffix", func(T *testing.T) {
+		mt := api.ParseMediaType("Application/Problem+JSON; charset=UTF-8")
+
+		assert.Equal(T, "application", mt.Type)
+		assert.Equal(T, "problem+json", mt.Subtype)
+		assert.Equal(T, "json", mt.Suffix)
+		assert.Equal(T, "UTF-8", mt.Params["charset"])
+		assert.True(T, mt.IsJSON())
+	})
+
+	T.Run("Malformed parameters", func(T *testing.T) {
+		mt := api.ParseMediaType("application/json; charset")
+
+		assert.Equal(T, "application/json", mt.Essence())
+		assert.True(T, mt.IsJSON())
+	})
+
+	T.Run("Kinds", func(T *testing.T) {
+		assert.True(T, api.ParseMediaType("application/soap+xml").IsXML())
+		assert.True(T, api.ParseMediaType("application/x-ndjson").IsNDJSON())
+		assert.True(T, api.ParseMediaType("text/event-stream").IsEventStream())
+		assert.True(T, api.ParseMediaType("text/csv").IsCSV())
+		assert.True(T, api.ParseMediaType("text/plain").IsText())
+		assert.True(T, api.ParseMediaType("image/png").IsBinary())
+		assert.True(T, api.ParseMediaType("application/octet-stream").IsBinary())
+		assert.False(T, api.ParseMediaType("application/json").IsBinary())
+	})
+
+	T.Run("Not JSON", func(T *testing.T) {
+		assert.False(T, api.ParseMediaType("application/xml").IsJSON())
+		assert.False(T, api.ParseMediaType("text/jsonish").IsJSON())
+	})
+}
+
+func Test_MatchMediaType(T *testing.T) {
+	T.Run("Literal", func(T *testing.T) {
+		assert.True(T, api.MatchMediaType("application/json", "application/json; charset=utf-8"))
+		assert.False(T, api.MatchMediaType("application/json", "application/vnd.api+json"))
+	})
+
+	T.Run("Wildcards", func(T *testing.T) {
+		assert.True(T, api.MatchMediaType("*/*", "image/png"))
+		assert.True(T, api.MatchMediaType("*", "image/png"))
+		assert.True(T, api.MatchMediaType("application/*", "application/problem+json"))
+		assert.False(T, api.MatchMediaType("application/*", "text/plain"))
+	})
+
+	T.Run("Suffix wildcard", func(T *testing.T) {
+		assert.True(T, api.MatchMediaType("application/*+json", "application/vnd.api+json"))
+		assert.False(T, api.MatchMediaType("application/*+json", "application/vnd.api+xml"))
+	})
+
+	T.Run("Charset", func(T *testing.T) {
+		assert.True(T, api.MatchMediaType("text/plain; charset=utf-8", "text/plain; charset=UTF-8"))
+		assert.False(T, api.MatchMediaType("text/plain; charset=utf-8", "text/plain; charset=latin1"))
+		assert.False(T, api.MatchMediaType("text/plain; charset=utf-8", "text/plain"))
+	})
+}
+
+func Test_SelectMediaType(T *testing.T) {
+	keys := []string{
+		"*/*",
+		"application/*",
+		"application/*+json",
+		"application/json",
+	}
+
+	T.Run("Exact", func(T *testing.T) {
+		key, ok := api.SelectMediaType(keys, "application/json")
+		assert.True(T, ok)
+		assert.Equal(T, "application/json", key)
+	})
+
+	T.Run("Suffix", func(T *testing.T) {
+		key, ok := api.SelectMediaType(keys, "application/problem+json")
+		assert.True(T, ok)
+		assert.Equal(T, "application/*+json", key)
+	})
+
+	T.Run("Subtype wildcard", func(T *testing.T) {
+		key, ok := api.SelectMediaType(keys, "application/xml")
+		assert.True(T, ok)
+		assert.Equal(T, "application/*", key)
+	})
+
+	T.Run("Any", func(T *testing.T) {
+		key, ok := api.SelectMediaType(keys, "image/png")
+		assert.True(T, ok)
+		assert.Equal(T, "*/*", key)
+	})
+
+	T.Run("Wildcard CT", func(T *testing.T) {
+		key, ok := api.SelectMediaType([]string{"application/xml", "text/plain"}, "application/*")
+		assert.True(T, ok)
+		assert.Equal(T, "application/xml", key)
+	})
+
+	T.Run("None", func(T *testing.T) {
+		_, ok := api.SelectMediaType([]string{"application/json"}, "image/png")
+		assert.False(T, ok)
+	})
+}
@@ -79,3 +79,9 @@ func (op NullOperation) Result() *contract.OperationResult {
 	op.Report()
 	return nil
 }
+
+// Clone reports an error.
+func (op NullOperation) Clone() contract.Operation {
+	op.Report()
+	return &op
+}
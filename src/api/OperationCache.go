@@ -6,7 +6,8 @@ import (
 )
 
 // OperationCache proxies the GetOperation method
-// and caches returned operations.
+// and caches returned operations. The cached operations serve
+// as prototypes for the NewOperation method.
 type OperationCache struct {
 	specs map[string]contract.OperationAccess
 	stash map[string]contract.Operation
@@ -35,3 +36,14 @@ func (cache OperationCache) GetOperation(id string) contract.Operation {
 
 	return cache.stash[id]
 }
+
+// GetSpec returns the spec by it's name, or nil.
+func (cache OperationCache) GetSpec(name string) contract.OperationAccess {
+	return cache.specs[name]
+}
+
+// NewOperation returns a new operation instance cloned from the cached one,
+// so it has it's own data, result & logger.
+func (cache OperationCache) NewOperation(id string) contract.Operation {
+	return cache.GetOperation(id).Clone()
+}
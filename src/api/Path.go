@@ -0,0 +1,48 @@
+package api
+
+import (
+	"strings"
+)
+
+// MatchPath matches a request path, like "/v2/pet/42", against a spec path
+// template, like "/pet/{petId}". The template is matched against the end
+// of the path, so server base paths don't matter. Returns the values
+// of the template parameters by their names.
+func MatchPath(template string, path string) (map[string]string, bool) {
+	tSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(pSegments) < len(tSegments) {
+		return nil, false
+	}
+
+	pSegments = pSegments[len(pSegments)-len(tSegments):]
+	params := map[string]string{}
+
+	for i, ts := range tSegments {
+		if strings.HasPrefix(ts, "{") && strings.HasSuffix(ts, "}") {
+			if pSegments[i] == "" {
+				return nil, false
+			}
+
+			params[ts[1:len(ts)-1]] = pSegments[i]
+		} else if ts != pSegments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// PathParams returns the names of the template parameters in order.
+func PathParams(template string) []string {
+	names := []string{}
+
+	for _, ts := range strings.Split(strings.Trim(template, "/"), "/") {
+		if strings.HasPrefix(ts, "{") && strings.HasSuffix(ts, "}") {
+			names = append(names, ts[1:len(ts)-1])
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,35 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+)
+
+func Test_MatchPath(T *testing.T) {
+	T.Run("Match", func(T *testing.T) {
+		params, ok := api.MatchPath("/pet/{petId}", "/v2/pet/42")
+		assert.True(T, ok)
+		assert.Equal(T, map[string]string{"petId": "42"}, params)
+	})
+
+	T.Run("Nested", func(T *testing.T) {
+		params, ok := api.MatchPath("/users/{userId}/orders/{orderId}", "/users/7/orders/9")
+		assert.True(T, ok)
+		assert.Equal(T, map[string]string{"userId": "7", "orderId": "9"}, params)
+	})
+
+	T.Run("Mismatch", func(T *testing.T) {
+		_, ok := api.MatchPath("/pet/{petId}", "/v2/store/42")
+		assert.False(T, ok)
+
+		_, ok = api.MatchPath("/pet/{petId}/photos", "/pet/42")
+		assert.False(T, ok)
+	})
+
+	T.Run("PathParams", func(T *testing.T) {
+		assert.Equal(T, []string{"userId", "orderId"}, api.PathParams("/users/{userId}/orders/{orderId}"))
+		assert.Equal(T, []string{}, api.PathParams("/pets"))
+	})
+}
@@ -36,3 +36,36 @@ func (schema *Schema) Cast(v string) interface{} {
 
 	return v
 }
+
+// Items returns a schema for the items of an array schema, so that streamed
+// content (like NDJSON lines or SSE events) can be validated item by item.
+// Non-array schemas are returned as they are. The spec components
+// are carried over to the item schema to keep $refs working.
+func (schema *Schema) Items() *Schema {
+	if schema.JSONSchema["type"] != "array" {
+		return schema
+	}
+
+	items := JSONSchema{}
+
+	switch v := schema.JSONSchema["items"].(type) {
+	case JSONSchema:
+		for k, kv := range v {
+			items[k] = kv
+		}
+
+	case map[string]interface{}:
+		for k, kv := range v {
+			items[k] = kv
+		}
+	}
+
+	if comps, ok := schema.JSONSchema["components"]; ok {
+		items["components"] = comps
+	}
+
+	return &Schema{
+		Name:       schema.Name + " item",
+		JSONSchema: items,
+	}
+}
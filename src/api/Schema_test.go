@@ -48,3 +48,38 @@ func Test_Schema(T *testing.T) {
 		assert.Equal(T, "foobar", schema.Cast("foobar"))
 	})
 }
+
+func Test_Schema_Items(T *testing.T) {
+	T.Run("Array", func(T *testing.T) {
+		schema := &api.Schema{
+			Name: "Pets",
+			JSONSchema: api.JSONSchema{
+				"type": "array",
+				"items": map[string]interface{}{
+					"$ref": "#/components/schemas/Pet",
+				},
+				"components": map[string]interface{}{},
+			},
+		}
+
+		expected := &api.Schema{
+			Name: "Pets item",
+			JSONSchema: api.JSONSchema{
+				"$ref":       "#/components/schemas/Pet",
+				"components": map[string]interface{}{},
+			},
+		}
+
+		assert.Equal(T, expected, schema.Items())
+	})
+
+	T.Run("Not an array", func(T *testing.T) {
+		schema := &api.Schema{
+			JSONSchema: api.JSONSchema{
+				"type": "object",
+			},
+		}
+
+		assert.Equal(T, schema, schema.Items())
+	})
+}
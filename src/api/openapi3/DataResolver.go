@@ -27,6 +27,9 @@ type DataResolver struct {
 	Spec          *openapi3.Swagger
 	Op            *Operation
 	SpecResponses *openapi3.Responses
+
+	// Drift is set in strict mode, see Strict().
+	Drift *contract.DriftReport
 }
 
 // ResolverExpectedHeader is header to expect.
@@ -180,11 +183,20 @@ func (resolver *DataResolver) Security(name string) contract.Security {
 	return security.Insecurity(resolver.Log)
 }
 
+// Strict enables the strict conformance mode. Validators created
+// afterwards also test for things not declared in the spec,
+// such as status codes, headers & response properties,
+// and report them to the drift report.
+func (resolver *DataResolver) Strict(report *contract.DriftReport) {
+	resolver.Drift = report
+}
+
 // Response returns a Validator instance to test response correctness.
 // Since there may be multiple responses in a OAS spec file, it selects
 // on of them based on the arguments.
 // If no status is supplied then 200 is used by default.
-// If no CT is supplied then "application/json" is used by default.
+// If no CT is supplied then "application/json" is used by default,
+// or the first content type from the spec when there is no JSON response.
 func (resolver *DataResolver) Response(status int64, CT string) contract.Validator {
 	v := test.NewValidator(resolver.Log)
 
@@ -209,9 +221,43 @@ func (resolver *DataResolver) Response(status int64, CT string) contract.Validat
 		}
 	}
 
+	if resolver.Drift != nil {
+		resolver.StrictExpectations(specResp, specMT, v)
+	}
+
 	return v
 }
 
+// StrictExpectations populates the provided validator with strict mode expectations.
+func (resolver *DataResolver) StrictExpectations(specResp *openapi3.Response, mt *openapi3.MediaType, v contract.Validator) {
+	opID := resolver.Op.ID()
+
+	statuses := []string{}
+	for key := range *resolver.SpecResponses {
+		statuses = append(statuses, key)
+	}
+
+	sort.Strings(statuses)
+
+	headers := []string{}
+	for name := range specResp.Headers {
+		headers = append(headers, name)
+	}
+
+	v.Expect(expect.DeclaredStatus(opID, statuses, resolver.Drift, resolver.Log))
+	v.Expect(expect.DeclaredHeaders(opID, headers, resolver.Drift, resolver.Log))
+
+	if mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+		if schema, err := resolver.MakeSchema("Response", mt.Schema.Value); err == nil {
+			v.Expect(expect.DeclaredProperties(opID, schema, resolver.Drift, resolver.Log))
+		}
+	}
+
+	if schema := resolver.RequestBodySchema(); schema != nil {
+		v.Expect(expect.ReadOnlyBody(opID, schema, resolver.Op.Data().Body, resolver.Drift, resolver.Log))
+	}
+}
+
 // MetaData populates the provided validator with expectations for HTTP status & content type.
 func (resolver *DataResolver) MetaData(status int64, CT string) (
 	int,
@@ -248,16 +294,31 @@ func (resolver *DataResolver) MetaData(status int64, CT string) (
 	// Under status code keys there are Content-Typed responses.
 	// Selecting the needed one (or application/json as default).
 	ct, mt, err := func() (string, *openapi3.MediaType, error) {
-		if CT == "" {
+		defaultCT := CT == ""
+		if defaultCT {
 			CT = "application/json"
 			//TODO: log using default CT
 		}
 
 		if len(specResp.Content) > 0 {
-			mt := specResp.Content[CT]
-			if mt != nil {
-				return CT, mt, nil
+			// Content map keys may be wildcards ("application/*", "*/*")
+			// or carry parameters, so looking for the most specific match.
+			keys := []string{}
+			for key := range specResp.Content {
+				keys = append(keys, key)
+			}
+
+			if key, ok := api.SelectMediaType(keys, CT); ok {
+				return key, specResp.Content[key], nil
+			}
+
+			// Responses like images or CSV exports may have no JSON at all,
+			// so using the first one available when nothing was asked for.
+			if defaultCT {
+				sort.Strings(keys)
+				return keys[0], specResp.Content[keys[0]], nil
 			}
+
 			return "", nil, errors.NotFound("spec response", CT, nil)
 		}
 
@@ -280,7 +341,9 @@ func (resolver *DataResolver) Headers(specResp *openapi3.Response, v contract.Va
 	}
 
 	for _, eh := range headers {
-		v.Expect(expect.HeaderSchema(eh.Name, eh.Schema, resolver.Log))
+		if eh.Schema != nil {
+			v.Expect(expect.HeaderSchema(eh.Name, eh.Schema, resolver.Log))
+		}
 
 		if eh.Required {
 			v.Expect(expect.HeaderRequired(eh.Name, resolver.Log))
@@ -351,6 +414,45 @@ func (resolver *DataResolver) Content(mt *openapi3.MediaType, CT string, v contr
 	return nil
 }
 
+// RequestBodySchema creates an api.Schema instance for the operation request body.
+// The XML content is preferred because it's schema carries the XML objects
+// needed to encode XML bodies. Returns nil when there is no schema.
+func (resolver *DataResolver) RequestBodySchema() *api.Schema {
+	if resolver.Op.SpecOp.RequestBody == nil || resolver.Op.SpecOp.RequestBody.Value == nil {
+		return nil
+	}
+
+	content := resolver.Op.SpecOp.RequestBody.Value.Content
+
+	keys := []string{}
+	for key := range content {
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.Strings(keys)
+
+	key, ok := api.SelectMediaType(keys, "application/xml")
+	if !ok {
+		key = keys[0]
+	}
+
+	mt := content[key]
+	if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+		return nil
+	}
+
+	schema, err := resolver.MakeSchema("Request", mt.Schema.Value)
+	if err != nil {
+		return nil
+	}
+
+	return schema
+}
+
 // MakeSchema creates an api.Schema instance from available operation spec data.
 // The schema is later used to test response contents (headers & bodies) against it.
 func (resolver *DataResolver) MakeSchema(
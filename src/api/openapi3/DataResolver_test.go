@@ -1,6 +1,7 @@
 package openapi3_test
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/x1n13y84issmd42/oasis/src/api/security"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/log"
 	"github.com/x1n13y84issmd42/oasis/src/params"
@@ -147,6 +149,34 @@ func Test_DataResolver(T *testing.T) {
 		assert.Nil(T, actualSpecMT)
 	})
 
+	T.Run("MetaData/Wildcard", func(T *testing.T) {
+		log := log.NewPlain(0)
+		responses := kinopenapi3.Responses{
+			"200": {
+				Value: &kinopenapi3.Response{
+					Content: kinopenapi3.Content{
+						"*/*":              kinopenapi3.NewMediaType(),
+						"application/*":    kinopenapi3.NewMediaType(),
+						"application/json": kinopenapi3.NewMediaType(),
+					},
+				},
+			},
+		}
+		resolver := openapi3.NewDataResolver(log, spec.OAS, nil, &responses)
+
+		_, actualCT, _, _, err := resolver.MetaData(0, "application/vnd.api+json")
+		assert.Nil(T, err)
+		assert.Equal(T, "application/*", actualCT)
+
+		_, actualCT, _, _, err = resolver.MetaData(0, "application/json; charset=utf-8")
+		assert.Nil(T, err)
+		assert.Equal(T, "application/json", actualCT)
+
+		_, actualCT, _, _, err = resolver.MetaData(0, "text/plain")
+		assert.Nil(T, err)
+		assert.Equal(T, "*/*", actualCT)
+	})
+
 	T.Run("MakeSchema/InvalidSchema/Marshal", func(T *testing.T) {
 		resolver := openapi3.NewDataResolver(log.NewPlain(0), spec.OAS, nil, nil)
 
@@ -268,3 +298,33 @@ func Test_DataResolver_Security(T *testing.T) {
 		assert.IsType(T, expected, actual)
 	})
 }
+
+func Test_DataResolver_Strict(T *testing.T) {
+	log := log.NewPlain(0)
+	spec, _ := openapi3.Load("../../../spec/test/oas3.yaml", log)
+	op := (&openapi3.Spec{OAS: spec.OAS, Log: log}).GetOperation("getPetById").(*openapi3.Operation)
+
+	drift := contract.NewDriftReport()
+	op.Resolve().Strict(drift)
+
+	result := &contract.OperationResult{
+		Success: true,
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type":    []string{"application/json"},
+				"X-Expires-After": []string{"tomorrow"},
+				"X-Debug":         []string{"1"},
+			},
+		},
+		ResponseBytes: []byte(`{"name": "Rex", "photoUrls": [], "color": "brown"}`),
+	}
+
+	actual := op.Resolve().Response(0, "").Validate(result)
+
+	assert.False(T, actual.Success)
+	assert.Equal(T, []contract.Drift{
+		{Operation: "getPetById", Kind: "header", Subject: "X-Debug"},
+		{Operation: "getPetById", Kind: "property", Subject: "color"},
+	}, drift.Drifts())
+}
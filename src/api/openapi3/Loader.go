@@ -1,19 +1,44 @@
 package openapi3
 
 import (
+	"path/filepath"
+	"sync"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 )
 
+// swaggers caches the parsed spec files by their paths, so scripts
+// executed together in a suite parse every spec only once.
+var swaggers = struct {
+	sync.Mutex
+	oas map[string]*openapi3.Swagger
+}{oas: map[string]*openapi3.Swagger{}}
+
 // Load reads the spec file at path, parses it and returns parsed spec data.
+// A spec file is parsed only once, the Spec instances share the parsed data.
 func Load(path string, logger contract.Logger) (*Spec, error) {
-	oas, oasErr := openapi3.NewSwaggerLoader().LoadSwaggerFromFile(path)
-	if oasErr == nil {
-		return &Spec{
-			OAS: oas,
-			Log: logger,
-		}, nil
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+
+	swaggers.Lock()
+	defer swaggers.Unlock()
+
+	oas, ok := swaggers.oas[key]
+	if !ok {
+		var oasErr error
+		oas, oasErr = openapi3.NewSwaggerLoader().LoadSwaggerFromFile(path)
+		if oasErr != nil {
+			return nil, oasErr
+		}
+
+		swaggers.oas[key] = oas
 	}
 
-	return nil, oasErr
+	return &Spec{
+		OAS: oas,
+		Log: logger,
+	}, nil
 }
@@ -15,6 +15,13 @@ func Test_Loader(T *testing.T) {
 		assert.Nil(T, specerr)
 	})
 
+	T.Run("Cached", func(T *testing.T) {
+		spec1, _ := openapi3.Load("../../../spec/test/oas3.yaml", log.NewPlain(0))
+		spec2, _ := openapi3.Load("../../../spec/test/oas3.yaml", log.NewPlain(0))
+		assert.True(T, spec1 != spec2)
+		assert.True(T, spec1.OAS == spec2.OAS)
+	})
+
 	T.Run("Failure", func(T *testing.T) {
 		spec, specerr := openapi3.Load("A/VERY/WRONG/PATH.yaml", log.NewPlain(0))
 		assert.Nil(T, spec)
@@ -16,6 +16,8 @@ type Operation struct {
 	SpecOp        *openapi3.Operation
 
 	Resolver *DataResolver
+
+	Spec *Spec
 }
 
 // ID returns an operation ID.
@@ -48,3 +50,9 @@ func (op *Operation) Path() string {
 func (op *Operation) Resolve() contract.DataResolver {
 	return op.Resolver
 }
+
+// Clone creates a new instance of the operation from the same spec data,
+// with it's own data, result & logger.
+func (op *Operation) Clone() contract.Operation {
+	return op.Spec.MakeOperation(op.RequestMethod, op.SpecOp, op.RequestPath, op.SpecPath)
+}
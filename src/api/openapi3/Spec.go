@@ -110,6 +110,7 @@ func (spec *Spec) MakeOperation(
 		RequestPath:        oasPath,
 		SpecOp:             oasOp,
 		SpecPath:           oasPathItem,
+		Spec:               spec,
 	}
 
 	op.Resolver = NewDataResolver(op.Log, spec.OAS, op, &oasOp.Responses)
@@ -134,6 +135,7 @@ func (spec *Spec) MakeOperation(
 	Headers.StopRememberingSources()
 
 	Body := params.Body(op.Log)
+	Body.Schema = op.Resolver.RequestBodySchema()
 	op.Data().Body = Body
 	Body.StopRememberingSources()
 
@@ -46,4 +46,27 @@ func Test_Spec(T *testing.T) {
 
 		assert.Equal(T, "https://petstore.swagger.io/v2/user/GOPHER", op.Data().URL.String())
 	})
+
+	T.Run("Clone", func(T *testing.T) {
+		op := spec.GetOperation("deleteUser")
+		op2 := op.Clone()
+
+		assert.Equal(T, op.ID(), op2.ID())
+		assert.False(T, op.Data() == op2.Data())
+		assert.False(T, op.Result() == op2.Result())
+		assert.False(T, op.GetLogger() == op2.GetLogger())
+
+		pp := params.NewMemorySource("")
+		pp.Add("username", "GOPHER")
+		op.Data().URL.Load(pp)
+		op.Data().URL.Load(op.Resolve().Host(""))
+
+		pp2 := params.NewMemorySource("")
+		pp2.Add("username", "RUSTACEAN")
+		op2.Data().URL.Load(pp2)
+		op2.Data().URL.Load(op2.Resolve().Host(""))
+
+		assert.Equal(T, "https://petstore.swagger.io/v2/user/GOPHER", op.Data().URL.String())
+		assert.Equal(T, "https://petstore.swagger.io/v2/user/RUSTACEAN", op2.Data().URL.String())
+	})
 }
@@ -12,4 +12,8 @@ type DataResolver interface {
 	Host(hostHint string) ParameterSource
 	Security(secName string) Security
 	Response(status int64, CT string) Validator
+
+	// Strict enables the strict conformance mode, in which responses are tested
+	// for things not declared in the spec. Such gaps are collected in the report.
+	Strict(report *DriftReport)
 }
@@ -0,0 +1,66 @@
+package contract
+
+import (
+	"sort"
+	"sync"
+)
+
+// Drift is a gap between a spec and an actual API behaviour,
+// such as an undeclared status code, header or response property.
+// Drifts are collected in strict mode only.
+type Drift struct {
+	Operation string
+	Kind      string
+	Subject   string
+	Details   string
+}
+
+// DriftReport collects drifts found while testing operations.
+// It is safe for concurrent use.
+type DriftReport struct {
+	mutex  sync.Mutex
+	drifts map[Drift]bool
+}
+
+// NewDriftReport creates a new DriftReport instance.
+func NewDriftReport() *DriftReport {
+	return &DriftReport{
+		drifts: map[Drift]bool{},
+	}
+}
+
+// Add adds a drift to the report. Duplicates are reported once.
+func (report *DriftReport) Add(d Drift) {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	report.drifts[d] = true
+}
+
+// Drifts returns a list of the reported drifts
+// sorted by operation, kind & subject.
+func (report *DriftReport) Drifts() []Drift {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	drifts := []Drift{}
+	for d := range report.drifts {
+		drifts = append(drifts, d)
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		a, b := drifts[i], drifts[j]
+
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+
+		return a.Subject < b.Subject
+	})
+
+	return drifts
+}
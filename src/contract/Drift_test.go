@@ -0,0 +1,25 @@
+package contract_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+func Test_DriftReport(T *testing.T) {
+	report := contract.NewDriftReport()
+
+	report.Add(contract.Drift{Operation: "b", Kind: "status", Subject: "404"})
+	report.Add(contract.Drift{Operation: "a", Kind: "property", Subject: "z"})
+	report.Add(contract.Drift{Operation: "a", Kind: "header", Subject: "X-Foo"})
+	report.Add(contract.Drift{Operation: "b", Kind: "status", Subject: "404"})
+
+	expected := []contract.Drift{
+		{Operation: "a", Kind: "header", Subject: "X-Foo"},
+		{Operation: "a", Kind: "property", Subject: "z"},
+		{Operation: "b", Kind: "status", Subject: "404"},
+	}
+
+	assert.Equal(T, expected, report.Drifts())
+}
@@ -3,13 +3,14 @@ package contract
 import (
 	"strings"
 
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/xeipuuv/gojsonschema"
 )
 
 // Logger - interface for test execution loggers.
 type Logger interface {
 	Clone() Logger
-	Buffer(enabled bool)
+	SetOutput(out LogOutput) LogOutput
 
 	NOMESSAGE(msg string, args ...interface{})
 	Parameters(name string, params ParameterSource)
@@ -22,6 +23,10 @@ type Logger interface {
 	LoadingScript(path string)
 
 	PrintOperations(ops OperationIterator)
+	PrintDrifts(drifts []Drift)
+	PrintSummary(summary []ScriptNodeSummary)
+	PrintPlan(plan ScriptPlan)
+	PrintSuiteSummary(summary []ScriptSummary)
 	TestingProject(p ProjectInfo)
 	TestingOperation(res Operation)
 
@@ -29,6 +34,7 @@ type Logger interface {
 	SecurityHasNoData(sec Security)
 
 	Requesting(method string, url string)
+	ReceivedResponse(result *OperationResult)
 
 	UsingParameterExample(paramName string, in string, container string, value string)
 
@@ -39,6 +45,9 @@ type Logger interface {
 	ResponseHasWrongStatus(expectedStatus int, actualStatus int)
 	ResponseHasWrongContentType(expectedCT string, actualCT string)
 	ResponseHasWrongPropertyValue(propName string, expected string, actual string)
+	ResponseHasWrongContent(what string, expected string, actual string)
+	ResponseHasDrift(d Drift)
+	AssertionFailed(assertion string, actual string)
 
 	OperationOK()
 	OperationFail()
@@ -47,6 +56,12 @@ type Logger interface {
 	SchemaFail(schemaName string, errors []gojsonschema.ResultError)
 
 	ScriptExecutionStart(node string)
+	ExecutingNode(node string)
+	NodeSkipped(node string, reason string)
+	NodeRestored(node string)
+	TearingDown()
+	CleaningUp()
+	UsingExecutionGraph(graph gcontract.Graph)
 
 	XError(err error, style LogStyle, tab TabFn)
 
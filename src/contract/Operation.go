@@ -19,6 +19,8 @@ type Operation interface {
 	Resolve() DataResolver
 
 	GetRequest() (*http.Request, error)
+
+	Clone() Operation
 }
 
 // OperationData is an interface to access data from various sources
@@ -1,16 +1,31 @@
 package contract
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"sync"
 )
 
 // OperationResult describes the outcome of an operation test.
 // It is bo te used as a possible source of data for subsequent tests.
+//
+// Response bodies are kept in ResponseBytes, unless they are too big,
+// in which case they are spilled into ResponseFile. Use Body() & Size()
+// to access the body regardless of where it is stored.
 type OperationResult struct {
 	Success       bool
 	HTTPRequest   *http.Request
 	HTTPResponse  *http.Response
 	ResponseBytes []byte
+	ResponseFile  string
+	ResponseSize  int64
+
+	document     interface{}
+	documentErr  error
+	documentOnce sync.Once
 }
 
 // And creates a new OperationResult instance with the Success field assigned
@@ -21,5 +36,37 @@ func (r1 *OperationResult) And(r2 *OperationResult) *OperationResult {
 	}
 }
 
+// Body returns a reader for the response body.
+func (r1 *OperationResult) Body() (io.ReadCloser, error) {
+	if r1.ResponseFile != "" {
+		return os.Open(r1.ResponseFile)
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(r1.ResponseBytes)), nil
+}
+
+// Size returns the response body size in bytes.
+func (r1 *OperationResult) Size() int64 {
+	if r1.ResponseFile != "" {
+		return r1.ResponseSize
+	}
+
+	return int64(len(r1.ResponseBytes))
+}
+
+// DocumentDecoder is a function to decode a response body into a document.
+type DocumentDecoder func(*OperationResult) (interface{}, error)
+
+// Document returns the decoded response body. The body is decoded only once,
+// using the provided decoder function, and the result is shared by
+// all subsequent calls, so validation & references don't parse it repeatedly.
+func (r1 *OperationResult) Document(decode DocumentDecoder) (interface{}, error) {
+	r1.documentOnce.Do(func() {
+		r1.document, r1.documentErr = decode(r1)
+	})
+
+	return r1.document, r1.documentErr
+}
+
 // OperationResults is a map of operation results.
 type OperationResults map[string]*OperationResult
@@ -1,6 +1,8 @@
 package contract_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,3 +22,55 @@ func Test_OperationResult(T *testing.T) {
 	assert.False(T, resF.And(resT).Success)
 	assert.False(T, resT.And(resF).Success)
 }
+
+func Test_OperationResult_Body(T *testing.T) {
+	T.Run("Memory", func(T *testing.T) {
+		res := &contract.OperationResult{
+			ResponseBytes: []byte("hello"),
+		}
+
+		body, err := res.Body()
+		assert.Nil(T, err)
+
+		data, _ := ioutil.ReadAll(body)
+		assert.Equal(T, "hello", string(data))
+		assert.Equal(T, int64(5), res.Size())
+	})
+
+	T.Run("File", func(T *testing.T) {
+		file, _ := ioutil.TempFile("", "oasis-test-")
+		file.WriteString("hello from disk")
+		file.Close()
+		defer os.Remove(file.Name())
+
+		res := &contract.OperationResult{
+			ResponseFile: file.Name(),
+			ResponseSize: 15,
+		}
+
+		body, err := res.Body()
+		assert.Nil(T, err)
+		defer body.Close()
+
+		data, _ := ioutil.ReadAll(body)
+		assert.Equal(T, "hello from disk", string(data))
+		assert.Equal(T, int64(15), res.Size())
+	})
+}
+
+func Test_OperationResult_Document(T *testing.T) {
+	res := &contract.OperationResult{}
+	calls := 0
+
+	decode := func(r *contract.OperationResult) (interface{}, error) {
+		calls++
+		return "doc", nil
+	}
+
+	doc1, _ := res.Document(decode)
+	doc2, _ := res.Document(decode)
+
+	assert.Equal(T, "doc", doc1)
+	assert.Equal(T, "doc", doc2)
+	assert.Equal(T, 1, calls)
+}
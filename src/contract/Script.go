@@ -1,6 +1,10 @@
 package contract
 
-import gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+import (
+	"time"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+)
 
 // ScriptSecurity contains security values such as usernames & password and tokens.
 type ScriptSecurity struct {
@@ -23,5 +27,72 @@ type SecurityAccess struct {
 // Script is an interface to scenario scripts.
 type Script interface {
 	GetExecutionGraph() gcontract.Graph
+	GetTeardownGraph() gcontract.Graph
+	GetCleanupGraph() gcontract.Graph
 	GetSecurity(name string) *SecurityAccess
+	IsStrict() bool
+	GetParallelism() int
+	IsSequential() bool
+	GetRowParallelism() int
+	GetVars() map[string]string
+	SetSeed(seed int64)
+}
+
+// Script node execution statuses.
+const (
+	NodePassed   = "passed"
+	NodeFailed   = "failed"
+	NodeSkipped  = "skipped"
+	NodeRestored = "restored"
+)
+
+// ScriptSummary is an outcome of a single script execution in a suite.
+// Error is set when the script has failed to execute at all.
+type ScriptSummary struct {
+	Script   string
+	Success  bool
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration time.Duration
+	Error    string
+}
+
+// ScriptPlan is the execution plan of a script: it's nodes
+// in the execution order, and the dependencies between them.
+type ScriptPlan struct {
+	Script string
+	Nodes  []ScriptPlanNode
+}
+
+// ScriptPlanNode is a script node along with the spec operation it executes,
+// and the nodes it depends on. ForEach, If & Assert are the node's forEach,
+// if & assertions, if any.
+type ScriptPlanNode struct {
+	Node      string
+	Operation string
+	Method    string
+	Path      string
+	ForEach   string
+	If        string
+	Assert    []string
+	DependsOn []ScriptPlanDependency
+}
+
+// ScriptPlanDependency is a node some other node depends on,
+// along with the reasons for that, like "data" or "after".
+type ScriptPlanDependency struct {
+	Node    string
+	Reasons []string
+}
+
+// ScriptNodeSummary is an outcome of a single script node execution.
+// Items & ItemsPassed count the executions of forEach nodes.
+type ScriptNodeSummary struct {
+	Node        string
+	Operation   string
+	Status      string
+	Duration    time.Duration
+	Items       int
+	ItemsPassed int
 }
@@ -112,22 +112,92 @@ type ArgsExpect struct {
 	Status int64
 }
 
+// ArgsReport is what goes after the "report" command line argument.
+type ArgsReport struct {
+	Format string
+	Path   string
+}
+
+// ArgsSuite is what goes after the "execute suite" command line argument,
+// along with the clauses selecting the suite scripts.
+type ArgsSuite struct {
+	Path    string
+	Workers int64
+	Include []string
+	Exclude []string
+	Tags    []string
+}
+
+// ArgsPlan is what goes after the "plan" command line argument.
+// Format is the format to export the plan in, written to Path when it's set.
+type ArgsPlan struct {
+	Script string
+	Format string
+	Path   string
+}
+
 // Args is a program arguments.
 type Args struct {
-	Script   string
-	Spec     string
-	Host     string
-	Ops      []string
-	Use      ArgsUse
-	Expect   ArgsExpect
-	LogLevel int64
-	LogStyle string
+	Script      string
+	Spec        string
+	Host        string
+	Ops         []string
+	Use         ArgsUse
+	Expect      ArgsExpect
+	LogLevel    int64
+	LogStyle    string
+	BodyLimit   int64
+	Modes       []string
+	Workers     int64
+	Environment string
+	Seed        *int64
+	Report      ArgsReport
+	Suite       ArgsSuite
+	Plan        ArgsPlan
+
+	// Only & From select the script nodes to execute, see script.SelectOnly()
+	// & script.SelectFrom().
+	Only []string
+	From []string
+
+	// State is the file to save the script run state to,
+	// Resume is the one to resume the script from, see script.RunState.
+	State  string
+	Resume string
+}
+
+// HasMode tells whether the "in MODE mode" argument has been given.
+func (args *Args) HasMode(mode string) bool {
+	for _, m := range args.Modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Then adds an expression to the parser which calls fn when all
+// the preceding expressions have matched, so partial matches
+// of the parser have no side effects.
+func Then(parser *ssp.SSP, fn func()) *ssp.SSP {
+	parser.Expressions = append(parser.Expressions, func(cursor *ssp.Cursor) bool {
+		fn()
+		return true
+	})
+
+	return parser
 }
 
 // ParseArgs parses command line arguments into the args struct.
 func ParseArgs(args *Args) {
+	expSuite := ssp.Strings("execute", "suite").CaptureString(&args.Suite.Path)
 	expExecute := ssp.String("execute").CaptureString(&args.Script)
 	expFrom := ssp.String("from").CaptureString(&args.Spec)
+	expPlan := ssp.String("plan").CaptureString(&args.Plan.Script).Repeat(ssp.OneOf(
+		ssp.String("as").CaptureString(&args.Plan.Format),
+		ssp.String("to").CaptureString(&args.Plan.Path),
+	), 0, 2)
 	expTest := ssp.String("test").CaptureStringSlice(&args.Ops)
 	expHost := ssp.String("@").CaptureString(&args.Host)
 
@@ -135,7 +205,7 @@ func ParseArgs(args *Args) {
 
 	hPathParams := func(params []string) {
 		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+			pps := strings.SplitN(pp, "=", 2)
 			args.Use.PathParameters[pps[0]] = pps[1]
 		}
 	}
@@ -147,7 +217,7 @@ func ParseArgs(args *Args) {
 
 	hQueryParams := func(params []string) {
 		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+			pps := strings.SplitN(pp, "=", 2)
 			if args.Use.Query[pps[0]] == nil {
 				args.Use.Query[pps[0]] = []string{}
 			}
@@ -159,7 +229,7 @@ func ParseArgs(args *Args) {
 
 	hBodyProps := func(params []string) {
 		for _, pp := range params {
-			pps := strings.Split(pp, "=")
+			pps := strings.SplitN(pp, "=", 2)
 			args.Use.Body[pps[0]] = pps[1]
 		}
 	}
@@ -178,24 +248,83 @@ func ParseArgs(args *Args) {
 	), 0, 2)
 
 	expLogLevel := ssp.Strings("at", "level").CaptureInt64(&args.LogLevel)
-	expLogStyle := ssp.String("in").CaptureString(&args.LogStyle).String("style")
+	// The "in" clauses differ by their last word, so the values
+	// are only stored once the whole clause has matched.
+	logStyle := ""
+	expLogStyle := Then(ssp.String("in").CaptureString(&logStyle).String("style"), func() {
+		args.LogStyle = logStyle
+	})
+
 	expLog := ssp.String("log").Repeat(ssp.OneOf(
 		expLogLevel,
 		expLogStyle,
 	), 1, 2)
 
+	expBuffer := ssp.Strings("buffer", "up", "to").CaptureInt64(&args.BodyLimit).String("bytes")
+
+	mode := ""
+	expMode := Then(ssp.String("in").CaptureString(&mode).String("mode"), func() {
+		args.Modes = append(args.Modes, mode)
+	})
+
+	expEnvironment := ssp.Strings("in", "environment").CaptureString(&args.Environment)
+
+	seed := int64(0)
+	expSeed := Then(ssp.Strings("with", "seed").CaptureInt64(&seed), func() {
+		args.Seed = &seed
+	})
+
+	suiteWorkers := int64(0)
+	expSuiteWorkers := Then(ssp.String("with").CaptureInt64(&suiteWorkers).String("parallel").String("scripts"), func() {
+		args.Suite.Workers = suiteWorkers
+	})
+
+	expWorkers := ssp.String("with").CaptureInt64(&args.Workers).String("workers")
+
+	expIncluding := ssp.String("including").CaptureStringSlice(&args.Suite.Include)
+	expExcluding := ssp.String("excluding").CaptureStringSlice(&args.Suite.Exclude)
+	expTagged := ssp.String("tagged").CaptureStringSlice(&args.Suite.Tags)
+
+	expOnly := ssp.String("only").CaptureStringSlice(&args.Only)
+
+	expState := ssp.Strings("save", "state", "to").CaptureString(&args.State)
+	expResume := ssp.Strings("resume", "from").CaptureString(&args.Resume)
+
+	expReport := ssp.String("report").String("as").CaptureString(&args.Report.Format).String("to").CaptureString(&args.Report.Path)
+
 	ssp.Repeat(ssp.OneOf(
 		ssp.OneOf(
+			expSuite,
 			expExecute,
 			expFrom,
+			expPlan,
 		),
 		expTest,
 		expUse,
 		expExpect,
 		expHost,
 		expLog,
-	), 1, 6).Parse(os.Args[1:])
+		expBuffer,
+		expEnvironment,
+		expMode,
+		expSuiteWorkers,
+		expWorkers,
+		expSeed,
+		expReport,
+		expIncluding,
+		expExcluding,
+		expTagged,
+		expOnly,
+		expState,
+		expResume,
+	), 1, 20).Parse(os.Args[1:])
 	//    ^^^ UPDATE ME EVERY TIME YOU ADD ARGUMENTS
 
+	// A script needs no spec, so it's "from" names the nodes to start from.
+	if (args.Script != "" || args.Plan.Script != "") && args.Spec != "" {
+		args.From = strings.Split(args.Spec, ",")
+		args.Spec = ""
+	}
+
 	// fmt.Printf("Args: %#v\n", args)
 }
@@ -0,0 +1,526 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// JSON is a logger which emits one JSON object per event (NDJSON),
+// to be ingested by other programs. Every object has the "time" & "event"
+// fields, and the "op" & "node" fields when an operation is being tested.
+// The events are filtered by the same levels as the text loggers use.
+type JSON struct {
+	Level  int64
+	Output contract.LogOutput
+
+	Op    string
+	Node  string
+	Start time.Time
+}
+
+// JSONFields are the event-specific fields of a JSON log entry.
+type JSONFields map[string]interface{}
+
+// NewJSON creates a new JSON logger instance.
+func NewJSON(level int64) contract.Logger {
+	return &JSON{
+		Level:  level,
+		Output: NewStdOut(),
+	}
+}
+
+// Clone creates a copy of the log.
+func (log *JSON) Clone() contract.Logger {
+	log2 := *log
+	return &log2
+}
+
+// SetOutput sets a new log output and returns the previous one.
+func (log *JSON) SetOutput(out contract.LogOutput) contract.LogOutput {
+	prev := log.Output
+	log.Output = out
+	return prev
+}
+
+// Event emits a single JSON log entry.
+func (log *JSON) Event(l int64, event string, fields JSONFields) {
+	if l > log.Level {
+		return
+	}
+
+	entry := JSONFields{
+		"time":  time.Now().Format(time.RFC3339Nano),
+		"event": event,
+	}
+
+	if log.Op != "" {
+		entry["op"] = log.Op
+	}
+
+	if log.Node != "" {
+		entry["node"] = log.Node
+	}
+
+	for k, v := range fields {
+		entry[k] = v
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(JSONFields{
+			"time":  entry["time"],
+			"event": "error",
+			"error": err.Error(),
+		})
+	}
+
+	log.Output.Print("%s\n", data)
+}
+
+// Print emits a message event. Whitespace-only messages are skipped,
+// since they only make sense for text logs.
+func (log *JSON) Print(l int64, msg string, args ...interface{}) {
+	text := strings.TrimSpace(fmt.Sprintf(msg, args...))
+	if text != "" {
+		log.Event(l, "message", JSONFields{"message": text})
+	}
+}
+
+// NOMESSAGE emits a message event.
+func (log *JSON) NOMESSAGE(msg string, args ...interface{}) {
+	log.Print(1, msg, args...)
+}
+
+// Parameters emits a parameter event for every parameter in the source.
+func (log *JSON) Parameters(name string, params contract.ParameterSource) {
+	for p := range params.Iterate() {
+		log.Event(0, "parameter", JSONFields{
+			"container": name,
+			"name":      p.N,
+			"value":     p.V(),
+			"source":    p.Source,
+		})
+	}
+}
+
+// Usage emits the CLI usage information.
+func (log *JSON) Usage() {
+	log.Event(0, "usage", JSONFields{
+		"message": "Please specify at least a spec file & an operation to test.",
+		"example": "oasis from path/to/oas_spec.yaml test operation_id",
+	})
+}
+
+// Error emits an error event. errors.IError instances
+// carry their source & cause chain.
+func (log *JSON) Error(err error) {
+	log.Event(1, "error", JSONError(err))
+}
+
+// XError emits an error event.
+func (log *JSON) XError(err error, style contract.LogStyle, tab contract.TabFn) {
+	log.Error(err)
+}
+
+// JSONError converts an error into JSON log fields, recursively.
+func JSONError(err error) JSONFields {
+	fields := JSONFields{
+		"error": err.Error(),
+	}
+
+	if xerr, ok := err.(errors.IError); ok {
+		fields["source"] = xerr.Caller()
+
+		if c := xerr.Cause(); c != nil {
+			fields["cause"] = JSONError(c)
+		}
+	}
+
+	return fields
+}
+
+// LoadingSpec emits an event about the API specification being used.
+func (log *JSON) LoadingSpec(path string) {
+	log.Event(2, "loadingSpec", JSONFields{"path": path})
+}
+
+// LoadingScript emits an event about the script being used.
+func (log *JSON) LoadingScript(path string) {
+	log.Event(2, "loadingScript", JSONFields{"path": path})
+}
+
+// PrintOperations emits an event for every available operation.
+func (log *JSON) PrintOperations(ops contract.OperationIterator) {
+	for op := range ops {
+		log.Event(1, "operation", JSONFields{
+			"id":          op.ID(),
+			"name":        op.Name(),
+			"description": op.Description(),
+			"method":      op.Method(),
+			"path":        op.Path(),
+		})
+	}
+}
+
+// PrintDrifts emits an event for every spec drift found in strict mode.
+func (log *JSON) PrintDrifts(drifts []contract.Drift) {
+	for _, d := range drifts {
+		log.Event(1, "drift", JSONFields{
+			"operation": d.Operation,
+			"kind":      d.Kind,
+			"subject":   d.Subject,
+			"details":   d.Details,
+		})
+	}
+}
+
+// PrintSummary emits an event for every executed script node,
+// followed by a summary event with the totals.
+func (log *JSON) PrintSummary(summary []contract.ScriptNodeSummary) {
+	counts := map[string]int{}
+
+	for _, s := range summary {
+		counts[s.Status]++
+
+		fields := JSONFields{
+			"node":      s.Node,
+			"operation": s.Operation,
+			"status":    s.Status,
+			"duration":  float64(s.Duration.Microseconds()) / 1000,
+		}
+
+		if s.Items > 0 {
+			fields["items"] = s.Items
+			fields["itemsPassed"] = s.ItemsPassed
+		}
+
+		log.Event(1, "nodeSummary", fields)
+	}
+
+	fields := JSONFields{
+		contract.NodePassed:  counts[contract.NodePassed],
+		contract.NodeFailed:  counts[contract.NodeFailed],
+		contract.NodeSkipped: counts[contract.NodeSkipped],
+	}
+
+	if counts[contract.NodeRestored] > 0 {
+		fields[contract.NodeRestored] = counts[contract.NodeRestored]
+	}
+
+	log.Event(1, "summary", fields)
+}
+
+// PrintPlan emits an event for every script node in the execution order,
+// with it's request & the nodes it depends on, along with the reasons.
+func (log *JSON) PrintPlan(plan contract.ScriptPlan) {
+	for _, n := range plan.Nodes {
+		deps := []JSONFields{}
+		for _, d := range n.DependsOn {
+			deps = append(deps, JSONFields{
+				"node":    d.Node,
+				"reasons": d.Reasons,
+			})
+		}
+
+		fields := JSONFields{
+			"script":    plan.Script,
+			"node":      n.Node,
+			"operation": n.Operation,
+			"method":    n.Method,
+			"path":      n.Path,
+			"dependsOn": deps,
+		}
+
+		if n.ForEach != "" {
+			fields["forEach"] = n.ForEach
+		}
+
+		if n.If != "" {
+			fields["if"] = n.If
+		}
+
+		if len(n.Assert) > 0 {
+			fields["assert"] = n.Assert
+		}
+
+		log.Event(1, "planNode", fields)
+	}
+}
+
+// PrintSuiteSummary emits an event for every executed suite script,
+// followed by a suite summary event with the totals.
+func (log *JSON) PrintSuiteSummary(summary []contract.ScriptSummary) {
+	counts := map[string]int{}
+
+	for _, s := range summary {
+		status := contract.NodePassed
+		if !s.Success {
+			status = contract.NodeFailed
+		}
+
+		counts[status]++
+
+		fields := JSONFields{
+			"script":             s.Script,
+			"status":             status,
+			contract.NodePassed:  s.Passed,
+			contract.NodeFailed:  s.Failed,
+			contract.NodeSkipped: s.Skipped,
+			"duration":           float64(s.Duration.Microseconds()) / 1000,
+		}
+
+		if s.Error != "" {
+			fields["error"] = s.Error
+		}
+
+		log.Event(1, "scriptSummary", fields)
+	}
+
+	log.Event(1, "suiteSummary", JSONFields{
+		contract.NodePassed: counts[contract.NodePassed],
+		contract.NodeFailed: counts[contract.NodeFailed],
+	})
+}
+
+// TestingProject emits an event about the project being tested.
+func (log *JSON) TestingProject(pi contract.ProjectInfo) {
+	log.Event(2, "testingProject", JSONFields{
+		"title":   pi.Title(),
+		"version": pi.Version(),
+	})
+}
+
+// TestingOperation emits an event about an operation being tested.
+// Subsequent events carry the operation ID.
+func (log *JSON) TestingOperation(op contract.Operation) {
+	log.Op = op.ID()
+	log.Start = time.Now()
+
+	log.Event(1, "testingOperation", JSONFields{
+		"name":   op.Name(),
+		"method": op.Method(),
+		"path":   op.Path(),
+	})
+}
+
+// UsingSecurity emits an event about security mechanisms being used.
+func (log *JSON) UsingSecurity(sec contract.Security) {
+	log.Event(3, "usingSecurity", JSONFields{"security": sec.GetName()})
+}
+
+// SecurityHasNoData emits an event about security settings without data.
+func (log *JSON) SecurityHasNoData(sec contract.Security) {
+	log.Event(3, "securityHasNoData", JSONFields{"security": sec.GetName()})
+}
+
+// Requesting emits an event about an HTTP request being performed.
+func (log *JSON) Requesting(method string, URL string) {
+	log.Event(2, "requesting", JSONFields{
+		"method": method,
+		"url":    URL,
+	})
+}
+
+// ReceivedResponse emits an event about a response received.
+func (log *JSON) ReceivedResponse(result *contract.OperationResult) {
+	log.Event(5, "receivedResponse", JSONFields{
+		"status":      result.HTTPResponse.StatusCode,
+		"contentType": result.HTTPResponse.Header.Get("Content-Type"),
+		"size":        result.Size(),
+	})
+}
+
+// UsingParameterExample emits an event about a parameter example being used.
+func (log *JSON) UsingParameterExample(paramName string, in string, container string, value string) {
+	log.Event(5, "usingParameterExample", JSONFields{
+		"name":   paramName,
+		"in":     in,
+		"source": container,
+		"value":  value,
+	})
+}
+
+// Expecting emits an event about an expectation.
+func (log *JSON) Expecting(what string, v string) {
+	log.Event(5, "expecting", JSONFields{
+		"expectation": what,
+		"expected":    v,
+	})
+}
+
+// ExpectingProperty emits an event about a body property expectation.
+func (log *JSON) ExpectingProperty(what string, v string) {
+	log.Event(5, "expectingProperty", JSONFields{
+		"property": what,
+		"expected": v,
+	})
+}
+
+// HeaderHasNoValue emits an event about a required response header having no data.
+func (log *JSON) HeaderHasNoValue(hdr string) {
+	log.Event(1, "headerHasNoValue", JSONFields{"header": hdr})
+}
+
+// ResponseHasWrongStatus emits an event about an unexpected response status.
+func (log *JSON) ResponseHasWrongStatus(expectedStatus int, actualStatus int) {
+	log.Event(2, "responseHasWrongStatus", JSONFields{
+		"expected": expectedStatus,
+		"actual":   actualStatus,
+	})
+}
+
+// ResponseHasWrongContentType emits an event about an unexpected response Content-Type.
+func (log *JSON) ResponseHasWrongContentType(expectedCT string, actualCT string) {
+	log.Event(2, "responseHasWrongContentType", JSONFields{
+		"expected": expectedCT,
+		"actual":   actualCT,
+	})
+}
+
+// ResponseHasWrongPropertyValue emits an event about an unexpected body property value.
+func (log *JSON) ResponseHasWrongPropertyValue(propName string, expected string, actual string) {
+	log.Event(2, "responseHasWrongPropertyValue", JSONFields{
+		"property": propName,
+		"expected": expected,
+		"actual":   actual,
+	})
+}
+
+// ResponseHasWrongContent emits an event about unexpected response body properties.
+func (log *JSON) ResponseHasWrongContent(what string, expected string, actual string) {
+	log.Event(2, "responseHasWrongContent", JSONFields{
+		"expectation": what,
+		"expected":    expected,
+		"actual":      actual,
+	})
+}
+
+// AssertionFailed emits an event about a false script assertion.
+func (log *JSON) AssertionFailed(assertion string, actual string) {
+	log.Event(2, "assertionFailed", JSONFields{
+		"assertion": assertion,
+		"actual":    actual,
+	})
+}
+
+// ResponseHasDrift emits an event about a spec drift found in strict mode.
+func (log *JSON) ResponseHasDrift(d contract.Drift) {
+	log.Event(2, "responseHasDrift", JSONFields{
+		"kind":    d.Kind,
+		"subject": d.Subject,
+		"details": d.Details,
+	})
+}
+
+// OperationOK emits an event about a successful operation.
+func (log *JSON) OperationOK() {
+	log.Event(1, "operationOK", JSONFields{"duration": log.duration()})
+}
+
+// OperationFail emits an event about a failed operation.
+func (log *JSON) OperationFail() {
+	log.Event(1, "operationFail", JSONFields{"duration": log.duration()})
+}
+
+// duration returns the time in milliseconds since the operation testing start.
+func (log *JSON) duration() float64 {
+	if log.Start.IsZero() {
+		return 0
+	}
+
+	return float64(time.Since(log.Start).Microseconds()) / 1000
+}
+
+// SchemaOK emits an event about a successful schema validation.
+func (log *JSON) SchemaOK(schemaName string) {
+	log.Event(4, "schemaOK", JSONFields{"schema": schemaName})
+}
+
+// SchemaFail emits an event about a failed schema validation,
+// with an entry for every validation error.
+func (log *JSON) SchemaFail(schemaName string, errors []gojsonschema.ResultError) {
+	errs := []JSONFields{}
+
+	for _, err := range errors {
+		errs = append(errs, JSONFields{
+			"field":       err.Field(),
+			"type":        err.Type(),
+			"description": err.Description(),
+			"value":       err.Value(),
+		})
+	}
+
+	log.Event(4, "schemaFail", JSONFields{
+		"schema": schemaName,
+		"errors": errs,
+	})
+}
+
+// ScriptExecutionStart emits an event about the starting node of the script execution graph.
+func (log *JSON) ScriptExecutionStart(node string) {
+	log.Event(5, "scriptExecutionStart", JSONFields{"start": node})
+}
+
+// ExecutingNode emits an event about a script node being executed.
+// Subsequent events carry the node ID.
+func (log *JSON) ExecutingNode(node string) {
+	log.Node = node
+	log.Event(5, "executingNode", nil)
+}
+
+// TearingDown emits an event about the script teardown operations being executed.
+func (log *JSON) TearingDown() {
+	log.Event(1, "tearingDown", JSONFields{})
+}
+
+// CleaningUp emits an event about the created resources being deleted.
+func (log *JSON) CleaningUp() {
+	log.Event(1, "cleaningUp", JSONFields{})
+}
+
+// NodeSkipped emits an event about a script node being skipped.
+func (log *JSON) NodeSkipped(node string, reason string) {
+	log.Event(1, "nodeSkipped", JSONFields{
+		"node":   node,
+		"reason": reason,
+	})
+}
+
+// NodeRestored emits an event about a script node result being restored from a run state.
+func (log *JSON) NodeRestored(node string) {
+	log.Event(1, "nodeRestored", JSONFields{
+		"node": node,
+	})
+}
+
+// UsingExecutionGraph emits an event with the script execution graph
+// nodes & edges. An edge from A to B means that A depends on B.
+func (log *JSON) UsingExecutionGraph(graph gcontract.Graph) {
+	nodes := []string{}
+	edges := [][]string{}
+
+	for n := range graph.Nodes().Range() {
+		nodes = append(nodes, string(n.ID()))
+
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			edges = append(edges, []string{string(n.ID()), string(an.ID())})
+		}
+	}
+
+	log.Event(5, "usingExecutionGraph", JSONFields{
+		"nodes": nodes,
+		"edges": edges,
+	})
+}
+
+// Flush flushes the output.
+func (log *JSON) Flush() {
+	log.Output.Flush()
+}
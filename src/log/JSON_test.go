@@ -0,0 +1,69 @@
+package log
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+func jsonEntries(T *testing.T, out *BufferedStdOut) []JSONFields {
+	entries := []JSONFields{}
+
+	for _, line := range strings.Split(strings.TrimSpace(out.data), "\n") {
+		entry := JSONFields{}
+		assert.Nil(T, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func Test_JSON(T *testing.T) {
+	T.Run("Events", func(T *testing.T) {
+		out := NewBufferedStdOut()
+		log := &JSON{Level: 5, Output: out}
+
+		log.ExecutingNode("node1")
+		log.ResponseHasWrongStatus(200, 404)
+		log.Print(1, "  ")
+		log.Print(1, "100%% done")
+
+		entries := jsonEntries(T, out)
+		assert.Len(T, entries, 3)
+
+		assert.Equal(T, "executingNode", entries[0]["event"])
+		assert.Equal(T, "node1", entries[0]["node"])
+
+		assert.Equal(T, "responseHasWrongStatus", entries[1]["event"])
+		assert.Equal(T, float64(200), entries[1]["expected"])
+		assert.Equal(T, float64(404), entries[1]["actual"])
+		assert.NotEmpty(T, entries[1]["time"])
+
+		assert.Equal(T, "100% done", entries[2]["message"])
+	})
+
+	T.Run("Level", func(T *testing.T) {
+		out := NewBufferedStdOut()
+		log := &JSON{Level: 1, Output: out}
+
+		log.Expecting("status", "200")
+		assert.Equal(T, "", out.data)
+	})
+
+	T.Run("Error", func(T *testing.T) {
+		out := NewBufferedStdOut()
+		log := &JSON{Level: 1, Output: out}
+
+		log.Error(errors.Oops("Outer.", goerrors.New("inner")))
+
+		entry := jsonEntries(T, out)[0]
+		assert.Equal(T, "error", entry["event"])
+		assert.Equal(T, "Outer.", entry["error"])
+		assert.Contains(T, entry["source"], "JSON_test.go")
+		assert.Equal(T, map[string]interface{}{"error": "inner"}, entry["cause"])
+	})
+}
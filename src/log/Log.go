@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/errors"
@@ -26,11 +28,15 @@ func New(style string, level int64) contract.Logger {
 
 	case "festive":
 		return NewFestive(level)
+
+	case "json", "ndjson":
+		return NewJSON(level)
 	}
 
 	fmt.Printf("The \"%s\" log style is unknown.\nAvailable loggers are:\n", style)
 	fmt.Println("\tplain - a plain text logger")
 	fmt.Println("\tfestive - a nicer colorized logger")
+	fmt.Println("\tjson - one JSON object per event, for programmatic use")
 
 	os.Exit(1)
 
@@ -61,13 +67,11 @@ func (log *Log) Clone() contract.Logger {
 	return &log2
 }
 
-// Buffer enabkes buffering.
-func (log *Log) Buffer(enabled bool) {
-	if enabled {
-		log.Output = NewBufferedStdOut()
-	} else {
-		log.Output = NewStdOut()
-	}
+// SetOutput sets a new log output and returns the previous one.
+func (log *Log) SetOutput(out contract.LogOutput) contract.LogOutput {
+	prev := log.Output
+	log.Output = out
+	return prev
 }
 
 // Print prints.
@@ -93,7 +97,7 @@ func (log *Log) NOMESSAGE(msg string, args ...interface{}) {
 func (log *Log) Parameters(name string, params contract.ParameterSource) {
 	log.Println(0, "Contents of %s", name)
 	for p := range params.Iterate() {
-		fmt.Printf("%s = %s (from %s)\n", log.Style.ID(p.N), log.Style.Value(p.V()), p.Source)
+		log.Println(0, "%s = %s (from %s)", log.Style.ID(p.N), log.Style.Value(p.V()), p.Source)
 	}
 	log.Println(0, "")
 }
@@ -177,6 +181,11 @@ func (log *Log) Requesting(method string, URL string) {
 	log.Println(2, "\tRequesting %s @ %s", log.Style.Method(method), log.Style.URL(URL))
 }
 
+// ReceivedResponse informs about a response received.
+func (log *Log) ReceivedResponse(result *contract.OperationResult) {
+	log.Println(5, "\tReceived %s, %s bytes.", log.Style.Value(result.HTTPResponse.Status), log.Style.Value(result.Size()))
+}
+
 // UsingParameterExample informs that a parameter example being used.
 func (log *Log) UsingParameterExample(paramName string, in string, container string, value string) {
 	log.Println(5, "\tUsing the %s parameter %s %s (from %s).", in, log.Style.ID(paramName), log.Style.Value(value), container)
@@ -234,6 +243,209 @@ func (log *Log) ResponseHasWrongPropertyValue(propName string, expected string,
 	log.Println(2, m, log.Style.ID(propName), log.Style.ValueExpected(expected), log.Style.ValueActual(actual))
 }
 
+// ResponseHasWrongContent informs that the received response body has wrong/unexpected properties,
+// such as size or checksum.
+func (log *Log) ResponseHasWrongContent(what string, expected string, actual string) {
+	m := strings.Join([]string{
+		"\t",
+		"Expected the response %s to be %s ",
+		"but got %s",
+		".",
+	}, "")
+
+	log.Println(2, m, log.Style.ID(what), log.Style.ValueExpected(expected), log.Style.ValueActual(actual))
+}
+
+// ResponseHasDrift informs that the response has something not declared
+// in the spec, or violates it's readOnly/writeOnly constraints. Used in strict mode.
+func (log *Log) ResponseHasDrift(d contract.Drift) {
+	if d.Details != "" {
+		log.Println(2, "\tThe %s %s violates the spec%s.", d.Kind, log.Style.ID(d.Subject), driftDetails(d))
+		return
+	}
+
+	log.Println(2, "\tThe %s %s is not declared in the spec.", d.Kind, log.Style.ID(d.Subject))
+}
+
+// PrintDrifts prints a drift report, the list of spec gaps found in strict mode.
+func (log *Log) PrintDrifts(drifts []contract.Drift) {
+	if len(drifts) == 0 {
+		log.Println(1, "%s", log.Style.Success("No spec drifts found."))
+		return
+	}
+
+	log.Println(1, "Found %s spec drift(s):", log.Style.Value(len(drifts)))
+
+	for _, d := range drifts {
+		log.Println(1, "\t%s: %s %s%s", log.Style.Op(d.Operation), d.Kind, log.Style.ID(d.Subject), driftDetails(d))
+	}
+}
+
+func driftDetails(d contract.Drift) string {
+	if d.Details == "" {
+		return ""
+	}
+
+	return " (" + d.Details + ")"
+}
+
+// PrintSummary prints a table of the executed script nodes
+// with their statuses & durations, followed by the totals.
+func (log *Log) PrintSummary(summary []contract.ScriptNodeSummary) {
+	nodeW, opW, statusW := len("Node"), len("Operation"), len(contract.NodeSkipped)
+	for _, s := range summary {
+		if len(s.Node) > nodeW {
+			nodeW = len(s.Node)
+		}
+
+		if len(s.Operation) > opW {
+			opW = len(s.Operation)
+		}
+
+		if len(s.Status) > statusW {
+			statusW = len(s.Status)
+		}
+	}
+
+	counts := map[string]int{}
+
+	log.Println(1, "")
+	log.Println(1, "\t%-*s  %-*s  %-*s  %s", nodeW, "Node", opW, "Operation", statusW, "Status", "Duration")
+
+	for _, s := range summary {
+		counts[s.Status]++
+
+		status := fmt.Sprintf("%-*s", statusW, s.Status)
+		switch s.Status {
+		case contract.NodePassed, contract.NodeRestored:
+			status = log.Style.OK(status)
+
+		case contract.NodeFailed:
+			status = log.Style.Failure(status)
+		}
+
+		duration := "-"
+		if s.Status != contract.NodeSkipped && s.Status != contract.NodeRestored {
+			duration = s.Duration.Round(time.Millisecond).String()
+		}
+
+		if s.Items > 0 {
+			duration += fmt.Sprintf(", %d of %d items passed", s.ItemsPassed, s.Items)
+		}
+
+		log.Println(1, "\t%-*s  %-*s  %s  %s", nodeW, s.Node, opW, s.Operation, status, duration)
+	}
+
+	log.Println(1, "")
+	restored := ""
+	if counts[contract.NodeRestored] > 0 {
+		restored = fmt.Sprintf(", %s restored", log.Style.Value(counts[contract.NodeRestored]))
+	}
+
+	log.Println(1, "%s passed, %s failed, %s skipped%s.",
+		log.Style.Value(counts[contract.NodePassed]),
+		log.Style.Value(counts[contract.NodeFailed]),
+		log.Style.Value(counts[contract.NodeSkipped]),
+		restored,
+	)
+}
+
+// PrintPlan prints a table of the script nodes in the execution order
+// with their requests & the nodes they depend on, along with the reasons.
+func (log *Log) PrintPlan(plan contract.ScriptPlan) {
+	nodeW, opW, reqW := len("Node"), len("Operation"), len("Request")
+	for _, n := range plan.Nodes {
+		if len(n.Node) > nodeW {
+			nodeW = len(n.Node)
+		}
+
+		if len(n.Operation) > opW {
+			opW = len(n.Operation)
+		}
+
+		if len(n.Method)+1+len(n.Path) > reqW {
+			reqW = len(n.Method) + 1 + len(n.Path)
+		}
+	}
+
+	log.Println(1, "")
+	log.Println(1, "Execution plan of %s:", log.Style.ID(plan.Script))
+	log.Println(1, "")
+	log.Println(1, "\t%-*s  %-*s  %-*s  %s", nodeW, "Node", opW, "Operation", reqW, "Request", "Depends on")
+
+	for _, n := range plan.Nodes {
+		deps := []string{}
+		for _, d := range n.DependsOn {
+			deps = append(deps, fmt.Sprintf("%s (%s)", d.Node, strings.Join(d.Reasons, ", ")))
+		}
+
+		if len(deps) == 0 {
+			deps = append(deps, "-")
+		}
+
+		log.Println(1, "\t%s  %-*s  %-*s  %s",
+			log.Style.Op(fmt.Sprintf("%-*s", nodeW, n.Node)),
+			opW, n.Operation,
+			reqW, n.Method+" "+n.Path,
+			strings.Join(deps, ", "),
+		)
+
+		if n.ForEach != "" {
+			log.Println(1, "\t%-*s  forEach %s", nodeW, "", log.Style.Value(n.ForEach))
+		}
+
+		if n.If != "" {
+			log.Println(1, "\t%-*s  if %s", nodeW, "", log.Style.Value(n.If))
+		}
+
+		for _, a := range n.Assert {
+			log.Println(1, "\t%-*s  assert %s", nodeW, "", log.Style.Value(a))
+		}
+	}
+
+	log.Println(1, "")
+	log.Println(1, "%s nodes.", log.Style.Value(len(plan.Nodes)))
+}
+
+// PrintSuiteSummary prints a table of the executed suite scripts
+// with their statuses & node counts, followed by the totals.
+func (log *Log) PrintSuiteSummary(summary []contract.ScriptSummary) {
+	scriptW := len("Script")
+	for _, s := range summary {
+		if len(s.Script) > scriptW {
+			scriptW = len(s.Script)
+		}
+	}
+
+	passed, failed := 0, 0
+
+	log.Println(1, "")
+	log.Println(1, "\t%-*s  %-7s  %6s  %6s  %7s  %s", scriptW, "Script", "Status", "Passed", "Failed", "Skipped", "Duration")
+
+	for _, s := range summary {
+		status := log.Style.OK(fmt.Sprintf("%-7s", contract.NodePassed))
+		passed++
+
+		if !s.Success {
+			status = log.Style.Failure(fmt.Sprintf("%-7s", contract.NodeFailed))
+			passed--
+			failed++
+		}
+
+		log.Println(1, "\t%-*s  %s  %6d  %6d  %7d  %s", scriptW, s.Script, status, s.Passed, s.Failed, s.Skipped, s.Duration.Round(time.Millisecond).String())
+
+		if s.Error != "" {
+			log.Println(1, "\t\t%s", log.Style.Failure(s.Error))
+		}
+	}
+
+	log.Println(1, "")
+	log.Println(1, "Scripts: %s passed, %s failed.",
+		log.Style.Value(passed),
+		log.Style.Value(failed),
+	)
+}
+
 // TestingOperation informs about an operation being tested.
 func (log *Log) TestingOperation(op contract.Operation) {
 	log.Print(1, "Testing the %s operation... ", log.Style.Op(op.Name()))
@@ -279,6 +491,43 @@ func (log *Log) ScriptExecutionStart(node string) {
 	log.Println(5, "Execution starts from the node %s.\n", log.Style.Op(node))
 }
 
+// ExecutingNode logs a script node being executed.
+func (log *Log) ExecutingNode(node string) {
+	log.Println(5, "Executing the node %s.", log.Style.Op(node))
+}
+
+// NodeSkipped informs that a script node has been skipped.
+func (log *Log) NodeSkipped(node string, reason string) {
+	log.Println(1, "Skipping the node %s: %s.", log.Style.Op(node), reason)
+}
+
+// NodeRestored informs that a script node result has been restored from a run state.
+func (log *Log) NodeRestored(node string) {
+	log.Println(1, "Restored the node %s from the run state.", log.Style.Op(node))
+}
+
+// AssertionFailed informs that a script assertion is false, with it's operand values.
+func (log *Log) AssertionFailed(assertion string, actual string) {
+	log.Println(1, "\tExpected %s, but got %s.", log.Style.ValueExpected(assertion), log.Style.ValueActual(actual))
+}
+
+// TearingDown informs that the script teardown operations are being executed.
+func (log *Log) TearingDown() {
+	log.Println(1, "")
+	log.Println(1, "Tearing down.")
+}
+
+// CleaningUp informs that the resources created by the script are being deleted.
+func (log *Log) CleaningUp() {
+	log.Println(1, "")
+	log.Println(1, "Cleaning up the created resources.")
+}
+
+// UsingExecutionGraph logs the script execution graph size.
+func (log *Log) UsingExecutionGraph(graph gcontract.Graph) {
+	log.Println(5, "The execution graph has %s nodes.", log.Style.Value(graph.Len()))
+}
+
 // Flush does nothing for the regular logger.
 func (log *Log) Flush() {
 	log.Output.Flush()
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+func Test_Log(T *testing.T) {
+	T.Run("PrintSummary", func(T *testing.T) {
+		out := NewBufferedStdOut()
+		log := &Log{Level: 1, Style: Plain{}, Output: out}
+
+		log.PrintSummary([]contract.ScriptNodeSummary{
+			{Node: "login", Operation: "auth.login", Status: contract.NodePassed, Duration: 1500 * time.Microsecond},
+			{Node: "createPet", Operation: "petstore.createPet", Status: contract.NodeFailed, Duration: 2 * time.Second},
+			{Node: "getPet", Operation: "petstore.getPet", Status: contract.NodeSkipped},
+		})
+
+		expected := "\n" +
+			"\tNode       Operation           Status   Duration\n" +
+			"\tlogin      auth.login          passed   2ms\n" +
+			"\tcreatePet  petstore.createPet  failed   2s\n" +
+			"\tgetPet     petstore.getPet     skipped  -\n" +
+			"\n" +
+			"1 passed, 1 failed, 1 skipped.\n"
+
+		assert.Equal(T, expected, out.data)
+	})
+
+	T.Run("PrintSummary restored", func(T *testing.T) {
+		out := NewBufferedStdOut()
+		log := &Log{Level: 1, Style: Plain{}, Output: out}
+
+		log.PrintSummary([]contract.ScriptNodeSummary{
+			{Node: "login", Operation: "auth.login", Status: contract.NodeRestored},
+			{Node: "getPet", Operation: "petstore.getPet", Status: contract.NodePassed, Duration: 2 * time.Millisecond},
+		})
+
+		expected := "\n" +
+			"\tNode    Operation        Status    Duration\n" +
+			"\tlogin   auth.login       restored  -\n" +
+			"\tgetPet  petstore.getPet  passed    2ms\n" +
+			"\n" +
+			"1 passed, 0 failed, 0 skipped, 1 restored.\n"
+
+		assert.Equal(T, expected, out.data)
+	})
+
+	T.Run("JSON style secrets", func(T *testing.T) {
+		Secret(`p&ss"word<\>`)
+
+		out := NewBufferedStdOut()
+		log := &JSON{Level: 5, Output: out}
+
+		log.Print(1, `Using p&ss"word<\>.`)
+		log.UsingParameterExample("password", "query", "script", `p&ss"word<\>`)
+
+		assert.NotContains(T, out.data, "p\\u0026ss")
+
+		entries := jsonEntries(T, out)
+		assert.Equal(T, "Using "+MaskString+".", entries[0]["message"])
+		assert.Equal(T, MaskString, entries[1]["value"])
+	})
+}
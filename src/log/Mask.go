@@ -0,0 +1,86 @@
+package log
+
+import (
+	"encoding/json"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaskString replaces secret values in log output.
+const MaskString = "*****"
+
+// SecretMinLength is the length of the shortest value masked in log output.
+// Shorter values, like "1" or "false", would mask too much of it.
+const SecretMinLength = 6
+
+// SecretHeaders are the request headers which values are always masked.
+var SecretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// secrets are the values which are never printed as is,
+// such as the script values coming from the environment.
+var secrets = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// Secret registers a value to be masked in all log output.
+// The URL- & HTML-encoded forms of the value are masked as well,
+// along with their JSON-escaped forms, as the JSON logs are masked
+// after being encoded. Values shorter than SecretMinLength are not masked.
+func Secret(value string) {
+	if len(value) < SecretMinLength {
+		return
+	}
+
+	secrets.Lock()
+	defer secrets.Unlock()
+
+	forms := []string{value, url.QueryEscape(value), url.PathEscape(value), html.EscapeString(value)}
+	for _, v := range forms {
+		data, _ := json.Marshal(v)
+		forms = append(forms, string(data[1:len(data)-1]))
+	}
+
+	for _, v := range forms {
+		known := false
+		for _, s := range secrets.values {
+			known = known || s == v
+		}
+
+		if !known {
+			secrets.values = append(secrets.values, v)
+		}
+	}
+
+	// Longer values first, so secrets containing other secrets are masked whole.
+	sort.SliceStable(secrets.values, func(i, j int) bool {
+		return len(secrets.values[i]) > len(secrets.values[j])
+	})
+}
+
+// Mask replaces all the registered secret values in s.
+func Mask(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+
+	for _, v := range secrets.values {
+		s = strings.Replace(s, v, MaskString, -1)
+	}
+
+	return s
+}
+
+// MaskHeader masks the value of the named HTTP header.
+// The SecretHeaders values are masked entirely.
+func MaskHeader(name string, value string) string {
+	for _, h := range SecretHeaders {
+		if strings.EqualFold(name, h) {
+			return MaskString
+		}
+	}
+
+	return Mask(value)
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Mask(T *testing.T) {
+	Secret("p@ss word")
+	Secret("")
+
+	assert.Equal(T, "password="+MaskString, Mask("password=p@ss word"))
+	assert.Equal(T, "?password="+MaskString, Mask("?password=p%40ss+word"))
+	assert.Equal(T, "nothing to hide", Mask("nothing to hide"))
+
+	out := NewBufferedStdOut()
+	out.Print("Using %s.", "p@ss word")
+	assert.Equal(T, "Using "+MaskString+".", out.data)
+
+	T.Run("Short", func(T *testing.T) {
+		Secret("false")
+		Secret("1")
+
+		assert.Equal(T, "id=1, ok=false", Mask("id=1, ok=false"))
+	})
+
+	T.Run("Headers", func(T *testing.T) {
+		assert.Equal(T, MaskString, MaskHeader("authorization", "Basic dXNlcjpwYXNz"))
+		assert.Equal(T, MaskString, MaskHeader("Cookie", "session=42"))
+		assert.Equal(T, MaskString, MaskHeader("Proxy-Authorization", "Bearer xyz"))
+		assert.Equal(T, "Bearer "+MaskString, MaskHeader("X-Token", "Bearer p@ss word"))
+		assert.Equal(T, "application/json", MaskHeader("Accept", "application/json"))
+	})
+}
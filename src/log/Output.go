@@ -2,13 +2,21 @@ package log
 
 import (
 	"fmt"
+	"os"
+	"sync"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
 )
 
+// stdout serializes writes to stdout from multiple outputs.
+var stdout sync.Mutex
+
 // BufferedStdOut is a buffered output for log.
 // It accumulates log output to flush it all at once.
-// Useful for concurrent script execution.
+// Useful for concurrent script execution. It is safe for concurrent use.
 type BufferedStdOut struct {
-	data string
+	mutex sync.Mutex
+	data  string
 }
 
 // NewBufferedStdOut creates a new Buffer instance.
@@ -18,15 +26,33 @@ func NewBufferedStdOut() *BufferedStdOut {
 
 // Flush flushes the accumulated output to stdout.
 func (buffer *BufferedStdOut) Flush() {
-	// fmt.Print("Flushing\n")
-	fmt.Printf(buffer.data)
+	buffer.mutex.Lock()
+	data := buffer.data
+	buffer.data = ""
+	buffer.mutex.Unlock()
+
+	stdout.Lock()
+	fmt.Print(data)
+	stdout.Unlock()
+}
+
+// FlushTo moves the accumulated output to another output.
+func (buffer *BufferedStdOut) FlushTo(out contract.LogOutput) {
+	buffer.mutex.Lock()
+	data := buffer.data
 	buffer.data = ""
+	buffer.mutex.Unlock()
+
+	out.Print("%s", data)
 }
 
 // Print collects the messages into an internal buffer.
 func (buffer *BufferedStdOut) Print(msg string, args ...interface{}) {
-	data := fmt.Sprintf(msg, args...)
+	data := Mask(fmt.Sprintf(msg, args...))
+
+	buffer.mutex.Lock()
 	buffer.data += data
+	buffer.mutex.Unlock()
 }
 
 // StdOut is a standard terminal output for Log.
@@ -41,9 +67,37 @@ func NewStdOut() StdOut {
 
 // Print collects the messages into an internal StdOut.
 func (out StdOut) Print(msg string, args ...interface{}) {
-	fmt.Printf(msg, args...)
+	data := Mask(fmt.Sprintf(msg, args...))
+
+	stdout.Lock()
+	fmt.Print(data)
+	stdout.Unlock()
 }
 
 // Flush does nothing for regular output.
 func (out StdOut) Flush() {
 }
+
+// StdErr is a standard error output for Log,
+// used when stdout is taken by some other output.
+type StdErr struct {
+	///
+}
+
+// NewStdErr creates a new StdErr instance.
+func NewStdErr() StdErr {
+	return StdErr{}
+}
+
+// Print prints the messages to stderr.
+func (out StdErr) Print(msg string, args ...interface{}) {
+	data := Mask(fmt.Sprintf(msg, args...))
+
+	stdout.Lock()
+	fmt.Fprint(os.Stderr, data)
+	stdout.Unlock()
+}
+
+// Flush does nothing for regular output.
+func (out StdErr) Flush() {
+}
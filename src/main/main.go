@@ -1,8 +1,13 @@
 package main
 
 import (
+	"os"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/env"
 	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/report"
+	"github.com/x1n13y84issmd42/oasis/src/test"
 )
 
 func main() {
@@ -13,13 +18,42 @@ func main() {
 
 	env.ParseArgs(args)
 
-	logger := log.New(args.LogStyle, args.LogLevel)
+	var logger contract.Logger = log.New(args.LogStyle, args.LogLevel)
+
+	if args.BodyLimit > 0 {
+		test.BodyMemoryLimit = args.BodyLimit
+	}
+
+	var collector *report.Collector
+	if args.Report.Path != "" {
+		collector = report.NewCollector()
+		logger = report.NewLogger(logger, collector)
+	}
+
+	success := true
 
-	if args.Script != "" {
-		Script(args, logger)
+	if args.Suite.Path != "" {
+		success = Suite(args, logger)
+	} else if args.Plan.Script != "" {
+		success = Plan(args, logger)
+	} else if args.Script != "" {
+		success = Script(args, logger)
 	} else if args.Spec != "" {
-		Manual(args, logger)
+		success = Manual(args, logger)
 	} else {
 		logger.Usage()
 	}
+
+	if collector != nil {
+		if err := report.Write(args.Report.Format, args.Report.Path, collector); err != nil {
+			logger.Error(err)
+			success = false
+		}
+	}
+
+	test.Cleanup()
+
+	if !success {
+		os.Exit(255)
+	}
 }
@@ -1,16 +1,16 @@
 package main
 
 import (
-	"os"
-
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/test"
 	"github.com/x1n13y84issmd42/oasis/src/utility"
 )
 
 // Manual is an entry point for manual testing mode.
-func Manual(args *env.Args, logger contract.Logger) {
+// Returns false when any of the tested operations has failed.
+func Manual(args *env.Args, logger contract.Logger) bool {
 	spec := utility.Load(args.Spec, logger)
 
 	logger.TestingProject(spec)
@@ -19,16 +19,25 @@ func Manual(args *env.Args, logger contract.Logger) {
 	specOps := utility.NewOperationResolver(spec, logger).Resolve(args.Ops)
 	result := test.Success()
 
+	var drift *contract.DriftReport
+	if args.HasMode("strict") {
+		drift = contract.NewDriftReport()
+	}
+
+	// CLI values may be templates, though without references.
+	tplContext := params.NewTemplateContext(logger)
+	tplContext.Seed = args.Seed
+
 	if len(specOps) > 0 {
 		for _, op := range specOps {
 			logger.TestingOperation(op)
 
 			// Stuffing it with data.
-			op.Data().URL.Load(args.Use.PathParameters)
+			op.Data().URL.Load(params.Templates(args.Use.PathParameters, tplContext.With(op.ID()+"/path")))
 			op.Data().URL.Load(op.Resolve().Host(args.Host))
-			op.Data().Query.Load(args.Use.Query)
-			op.Data().Headers.Load(args.Use.Headers)
-			op.Data().Body.Load(args.Use.Body)
+			op.Data().Query.Load(params.Templates(args.Use.Query, tplContext.With(op.ID()+"/query")))
+			op.Data().Headers.Load(params.Templates(args.Use.Headers, tplContext.With(op.ID()+"/headers")))
+			op.Data().Body.Load(params.Templates(args.Use.Body, tplContext.With(op.ID()+"/body")))
 
 			enrichment := []contract.RequestEnrichment{
 				op.Data().Query,
@@ -38,17 +47,22 @@ func Manual(args *env.Args, logger contract.Logger) {
 				op.Resolve().Security(args.Use.Security),
 			}
 
+			if drift != nil {
+				op.Resolve().Strict(drift)
+			}
+
 			v := op.Resolve().Response(args.Expect.Status, args.Expect.CT)
 
 			// Testing.
 			result = result.And(test.Operation(op, &enrichment, v, logger))
 		}
 
+		if drift != nil {
+			logger.PrintDrifts(drift.Drifts())
+		}
 	} else {
 		logger.PrintOperations(spec.Operations())
 	}
 
-	if !result.Success {
-		os.Exit(255)
-	}
+	return result.Success
 }
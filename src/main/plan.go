@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+// Plan is an entry point for previewing a script execution plan.
+// The plan is printed, or exported to a file or stdout when a format is given.
+// Returns true if the plan has been built.
+func Plan(args *env.Args, logger contract.Logger) bool {
+	path := args.Plan.Script
+
+	// The exported plan may go to stdout, so the log goes to stderr then.
+	if args.Plan.Format != "" && args.Plan.Path == "" {
+		logger.SetOutput(log.NewStdErr())
+	}
+
+	logger.LoadingScript(path)
+
+	graph := script.Load(path, args.Environment, logger).GetExecutionGraph()
+
+	if len(args.Only) > 0 {
+		graph = script.SelectOnly(graph, args.Only)
+	}
+
+	if len(args.From) > 0 {
+		graph = script.SelectFrom(graph, args.From)
+	}
+
+	plan, err := script.GetPlan(path, graph)
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	if args.Plan.Format == "" {
+		logger.PrintPlan(plan)
+		return true
+	}
+
+	out, err := script.ExportPlan(plan, args.Plan.Format)
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	if args.Plan.Path == "" {
+		fmt.Print(out)
+		return true
+	}
+
+	if err := ioutil.WriteFile(args.Plan.Path, []byte(out), 0644); err != nil {
+		logger.Error(errors.Oops("Failed to write the plan to '"+args.Plan.Path+"'.", err))
+		return false
+	}
+
+	return true
+}
@@ -1,17 +1,116 @@
 package main
 
 import (
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/env"
 	"github.com/x1n13y84issmd42/oasis/src/test/script"
 )
 
 // Script is an entry point for the scripted testing mode.
-func Script(args *env.Args, log contract.Logger) {
-	log.LoadingScript(args.Script)
+// Returns true if all the script nodes have succeeded.
+func Script(args *env.Args, log contract.Logger) bool {
+	success, _ := ExecuteScript(args.Script, args, log, nil)
+	return success
+}
+
+// ExecuteScript loads & executes the script at path, followed by
+// it's teardown & cleanup. The nodes output goes to out when it's set.
+// Returns true if all the script nodes have succeeded, and the nodes summary.
+func ExecuteScript(path string, args *env.Args, log contract.Logger, out contract.LogOutput) (bool, []contract.ScriptNodeSummary) {
+	log.LoadingScript(path)
+
+	s := script.Load(path, args.Environment, log)
+
+	// The CLI seed takes precedence over the script one.
+	if args.Seed != nil {
+		s.SetSeed(*args.Seed)
+	}
 
-	s := script.Load(args.Script, log)
 	graph := s.GetExecutionGraph()
 
-	script.NewExecutor(log, s).Execute(graph)
+	if len(args.Only) > 0 {
+		graph = script.SelectOnly(graph, args.Only)
+	}
+
+	if len(args.From) > 0 {
+		graph = script.SelectFrom(graph, args.From)
+	}
+
+	state, err := RunState(path, args, s, graph)
+	if err != nil {
+		log.Error(err)
+		return false, nil
+	}
+
+	ex := script.NewExecutor(log, s)
+	ex.Output = out
+	ex.State = state
+
+	if args.HasMode("strict") || s.IsStrict() {
+		ex.Drift = contract.NewDriftReport()
+	}
+
+	ex.Workers = s.GetParallelism()
+	if args.Workers > 0 {
+		ex.Workers = int(args.Workers)
+	}
+
+	ex.Sequential = args.HasMode("sequential") || s.IsSequential()
+	ex.Rows = s.GetRowParallelism()
+
+	success := ex.Execute(graph)
+
+	// Teardown failures are reported separately, and don't fail the tests.
+	if teardown := s.GetTeardownGraph(); teardown != nil {
+		ex.Teardown(teardown)
+	}
+
+	if cleanup := s.GetCleanupGraph(); cleanup != nil {
+		ex.CleanUp(cleanup)
+	}
+
+	if ex.Drift != nil {
+		log.PrintDrifts(ex.Drift.Drifts())
+	}
+
+	return success, ex.Summary
+}
+
+// RunState creates a run state to save the script progress to, or loads the one
+// to resume from, restoring the graph nodes passed in it. Nodes selected
+// with "from" are executed again. Returns nil when no state is requested.
+func RunState(path string, args *env.Args, s contract.Script, graph gcontract.Graph) (*script.RunState, error) {
+	if args.Resume == "" {
+		if args.State == "" {
+			return nil, nil
+		}
+
+		return script.NewRunState(args.State, path, s.GetVars()), nil
+	}
+
+	state, err := script.LoadRunState(args.Resume, path, s.GetVars())
+	if err != nil {
+		return nil, err
+	}
+
+	if args.State != "" {
+		state.Path = args.State
+	}
+
+	eg, ok := graph.(*script.ExecutionGraph)
+	if !ok {
+		return state, nil
+	}
+
+	rerun := map[gcontract.NodeID]bool{}
+	if len(args.From) > 0 {
+		if rerun, err = script.Dependents(eg, args.From); err != nil {
+			return nil, err
+		}
+	}
+
+	state.Restore(eg, rerun)
+
+	return state, nil
 }
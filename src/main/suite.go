@@ -0,0 +1,131 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/env"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/report"
+	"github.com/x1n13y84issmd42/oasis/src/test/script"
+)
+
+// Suite is an entry point for executing a directory of scripts.
+// Scripts are executed one by one by default, the output of the scripts
+// executed in parallel is printed once they are done.
+// Returns true if all the scripts have succeeded.
+func Suite(args *env.Args, logger contract.Logger) bool {
+	paths, err := script.DiscoverScripts(args.Suite.Path, script.SuiteFilter{
+		Include: args.Suite.Include,
+		Exclude: args.Suite.Exclude,
+		Tags:    args.Suite.Tags,
+	})
+
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	if len(paths) == 0 {
+		logger.Error(errors.Oops("No scripts found in '"+args.Suite.Path+"'.", nil))
+		return false
+	}
+
+	if rl, ok := logger.(*report.Logger); ok {
+		rl.Collector.Name = args.Suite.Path
+	}
+
+	workers := int(args.Suite.Workers)
+	if workers <= 0 {
+		workers = 1
+	}
+
+	summary := make([]contract.ScriptSummary, len(paths))
+	slots := make(chan struct{}, workers)
+	wg := sync.WaitGroup{}
+
+	for i, path := range paths {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			slog := suiteLogger(logger, path)
+
+			if workers > 1 {
+				out := log.NewBufferedStdOut()
+				slog.SetOutput(out)
+				defer out.Flush()
+
+				summary[i] = SuiteScript(path, args, slog, out)
+			} else {
+				summary[i] = SuiteScript(path, args, slog, nil)
+			}
+
+			// A script which failed to load has no cases to report it's error with.
+			if rl, ok := slog.(*report.Logger); ok && summary[i].Error != "" {
+				c := rl.Collector.Begin(path, "script")
+				c.Errors = append(c.Errors, summary[i].Error)
+				c.End(false)
+			}
+		}(i, path)
+	}
+
+	wg.Wait()
+
+	logger.PrintSuiteSummary(summary)
+
+	for _, s := range summary {
+		if !s.Success {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SuiteScript executes a suite script and summarizes it's outcome.
+// A script failing to load or execute doesn't stop the suite.
+func SuiteScript(path string, args *env.Args, log contract.Logger, out contract.LogOutput) (summary contract.ScriptSummary) {
+	summary.Script = path
+	start := time.Now()
+
+	defer func() {
+		summary.Duration = time.Since(start)
+
+		if r := recover(); r != nil {
+			summary.Success = false
+			summary.Error = "The script has failed to execute, see the error above."
+		}
+	}()
+
+	success, nodes := ExecuteScript(path, args, log, out)
+	summary.Success = success
+
+	for _, n := range nodes {
+		switch n.Status {
+		case contract.NodePassed, contract.NodeRestored:
+			summary.Passed++
+		case contract.NodeFailed:
+			summary.Failed++
+		default:
+			summary.Skipped++
+		}
+	}
+
+	return summary
+}
+
+// suiteLogger creates a logger for a suite script,
+// which reports into the script's own collector.
+func suiteLogger(logger contract.Logger, path string) contract.Logger {
+	if rl, ok := logger.(*report.Logger); ok {
+		return report.NewLogger(rl.Logger.Clone(), rl.Collector.Script(path))
+	}
+
+	return logger.Clone()
+}
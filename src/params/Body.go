@@ -1,6 +1,7 @@
 package params
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,14 +9,19 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
 	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/xml"
 )
 
 // BodyParameters is the source for request header parameters.
+// Schema is the request body schema from the spec, when available.
+// It is used to encode XML bodies.
 type BodyParameters struct {
 	contract.EntityTrait
 	*MultiSet
+	Schema *api.Schema
 }
 
 // Body creates a new BodyParameters instance.
@@ -60,14 +66,16 @@ func (params BodyParameters) Enrich(req *http.Request, log contract.Logger) {
 		return
 	}
 
+	CT := api.ParseMediaType(req.Header["Content-Type"][0])
+
 	// TODO: move this to some kind of body encoder
-	switch req.Header["Content-Type"][0] {
-	case "application/x-www-form-urlencoded":
+	switch {
+	case CT.Essence() == "application/x-www-form-urlencoded":
 		// log.NOMESSAGE("BodyParameters.Enrich: Body: application/x-www-form-urlencoded")
 		req.Body = ioutil.NopCloser(strings.NewReader(fd.Encode()))
 		break
 
-	case "application/json":
+	case CT.IsJSON():
 		// log.NOMESSAGE("BodyParameters.Enrich: Body: application/json")
 		json, jsonErr := json.Marshal(data)
 		if jsonErr != nil {
@@ -88,5 +96,19 @@ func (params BodyParameters) Enrich(req *http.Request, log contract.Logger) {
 		log.UsingParameterExample("Content-Length", "header", "computed", CL)
 
 		break
+
+	case CT.IsXML():
+		var schema xml.Schema
+		if params.Schema != nil {
+			schema = params.Schema.JSONSchema
+		}
+
+		xmlData, xmlErr := xml.Encode(data, schema)
+		if xmlErr != nil {
+			errors.Report(xmlErr, "BodyParameters", log)
+		}
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(xmlData))
+		req.ContentLength = int64(len(xmlData))
 	}
 }
@@ -0,0 +1,239 @@
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// conditionOps are the comparison operators, longer ones first.
+var conditionOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// Condition is a boolean expression, like
+// `#getPayment.response.status == pending && #getUser.response.age >= 18`.
+// It consists of comparisons joined with && & ||, where && takes precedence.
+// The compared values are template expressions, see Template,
+// and bare words, which are string literals.
+type Condition struct {
+	Source string
+	Or     [][]*Comparison
+}
+
+// Comparison compares two values. Numbers are compared as such,
+// anything else is compared as strings. Without an operator
+// it tells whether the Left value is truthy.
+type Comparison struct {
+	Left  *TemplateExpr
+	Op    string
+	Right *TemplateExpr
+}
+
+// ParseCondition parses a condition string.
+func ParseCondition(s string) (*Condition, error) {
+	cond := &Condition{Source: s}
+	and := []*Comparison{}
+	rest := s
+
+	for {
+		cmp, n, err := parseComparison(rest)
+		if err != nil {
+			return nil, errors.Oops(fmt.Sprintf("Failed to parse the condition '%s'.", s), err)
+		}
+
+		and = append(and, cmp)
+		rest = strings.TrimLeft(rest[n:], " ")
+
+		switch {
+		case rest == "":
+			cond.Or = append(cond.Or, and)
+			return cond, nil
+
+		case strings.HasPrefix(rest, "&&"):
+			rest = rest[2:]
+
+		case strings.HasPrefix(rest, "||"):
+			cond.Or = append(cond.Or, and)
+			and = []*Comparison{}
+			rest = rest[2:]
+
+		default:
+			return nil, errors.Oops(fmt.Sprintf("Failed to parse the condition '%s', unexpected %s.", s, rest), nil)
+		}
+	}
+}
+
+// parseComparison parses a comparison in the beginning of s.
+// It returns the comparison and number of parsed characters.
+func parseComparison(s string) (*Comparison, int, error) {
+	left, n, err := parseConditionOperand(s)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cmp := &Comparison{Left: left}
+	rest := strings.TrimLeft(s[n:], " ")
+
+	for _, op := range conditionOps {
+		if strings.HasPrefix(rest, op) {
+			i := len(s) - len(rest) + len(op)
+
+			right, rn, err := parseConditionOperand(s[i:])
+			if err != nil {
+				return nil, 0, err
+			}
+
+			cmp.Op = op
+			cmp.Right = right
+
+			return cmp, i + rn, nil
+		}
+	}
+
+	return cmp, n, nil
+}
+
+// parseConditionOperand parses a bare word or a template expression.
+func parseConditionOperand(s string) (*TemplateExpr, int, error) {
+	trimmed := strings.TrimLeft(s, " ")
+	n := len(s) - len(trimmed)
+
+	if name := templateIdentRx.FindString(trimmed); name != "" && !strings.HasPrefix(trimmed[len(name):], "(") {
+		return &TemplateExpr{Kind: TemplateLiteral, Value: name}, n + len(name), nil
+	}
+
+	return parseTemplateExpr(s)
+}
+
+// References returns a sorted list of the operations referenced in the condition.
+func (cond *Condition) References() []string {
+	tpl := &Template{}
+
+	for _, and := range cond.Or {
+		for _, cmp := range and {
+			tpl.Parts = append(tpl.Parts, cmp.Left)
+
+			if cmp.Right != nil {
+				tpl.Parts = append(tpl.Parts, cmp.Right)
+			}
+		}
+	}
+
+	return tpl.References()
+}
+
+// Eval computes the condition value.
+func (cond *Condition) Eval(ctx *TemplateContext) (bool, error) {
+	ctx = ctx.execution()
+
+	for _, and := range cond.Or {
+		res := true
+
+		for _, cmp := range and {
+			v, err := cmp.Eval(ctx)
+			if err != nil {
+				return false, errors.Oops(fmt.Sprintf("Failed to evaluate the condition '%s'.", cond.Source), err)
+			}
+
+			if !v {
+				res = false
+				break
+			}
+		}
+
+		if res {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Eval computes the comparison value.
+func (cmp *Comparison) Eval(ctx *TemplateContext) (bool, error) {
+	l, err := cmp.Left.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if cmp.Right == nil {
+		return IsTruthy(l), nil
+	}
+
+	r, err := cmp.Right.Eval(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	c := strings.Compare(l, r)
+
+	lf, lerr := strconv.ParseFloat(l, 64)
+	rf, rerr := strconv.ParseFloat(r, 64)
+	if lerr == nil && rerr == nil {
+		switch {
+		case lf < rf:
+			c = -1
+		case lf > rf:
+			c = 1
+		default:
+			c = 0
+		}
+	}
+
+	switch cmp.Op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+
+	return c >= 0, nil
+}
+
+// Explain describes the condition with it's operands evaluated,
+// like `120 == 99 || "" != paid`, to tell why it's false.
+// Operands which fail to evaluate are shown as ?.
+func (cond *Condition) Explain(ctx *TemplateContext) string {
+	ctx = ctx.execution()
+
+	operand := func(expr *TemplateExpr) string {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			return "?"
+		}
+
+		if v == "" {
+			return `""`
+		}
+
+		return v
+	}
+
+	or := []string{}
+	for _, and := range cond.Or {
+		cmps := []string{}
+		for _, cmp := range and {
+			if cmp.Right == nil {
+				cmps = append(cmps, operand(cmp.Left))
+			} else {
+				cmps = append(cmps, operand(cmp.Left)+" "+cmp.Op+" "+operand(cmp.Right))
+			}
+		}
+
+		or = append(or, strings.Join(cmps, " && "))
+	}
+
+	return strings.Join(or, " || ")
+}
+
+// IsTruthy tells whether v is neither empty, nor "false", "0" or "null".
+func IsTruthy(v string) bool {
+	return v != "" && v != "false" && v != "0" && v != "null"
+}
@@ -0,0 +1,77 @@
+package params_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+func evalCondition(T *testing.T, ctx *params.TemplateContext, s string) bool {
+	cond, err := params.ParseCondition(s)
+	assert.Nil(T, err)
+
+	v, err := cond.Eval(ctx)
+	assert.Nil(T, err)
+
+	return v
+}
+
+func Test_Condition(T *testing.T) {
+	ctx := params.NewTemplateContext(log.NewPlain(0))
+	ctx.Results["getPayment"] = &contract.OperationResult{
+		ResponseBytes: []byte(`{"status": "pending_dossier_approval", "amount": 120, "paid": false}`),
+	}
+
+	T.Run("Comparisons", func(T *testing.T) {
+		assert.True(T, evalCondition(T, ctx, "#getPayment.response.status == pending_dossier_approval"))
+		assert.True(T, evalCondition(T, ctx, `#getPayment.response.status != "paid"`))
+		assert.True(T, evalCondition(T, ctx, "#getPayment.response.amount > 99"))
+		assert.False(T, evalCondition(T, ctx, "#getPayment.response.amount <= 99.5"))
+		assert.True(T, evalCondition(T, ctx, "#getPayment.response.amount >= 120"))
+		assert.True(T, evalCondition(T, ctx, `"abc" < "abd"`))
+	})
+
+	T.Run("Truthiness", func(T *testing.T) {
+		assert.False(T, evalCondition(T, ctx, "#getPayment.response.paid"))
+		assert.True(T, evalCondition(T, ctx, "#getPayment.response.status"))
+		assert.False(T, evalCondition(T, ctx, `""`))
+	})
+
+	T.Run("Logic", func(T *testing.T) {
+		assert.True(T, evalCondition(T, ctx, "1 == 2 || 2 == 2 && 3 == 3"))
+		assert.False(T, evalCondition(T, ctx, "1 == 1 && 2 == 3 || 4 == 5"))
+		assert.True(T, evalCondition(T, ctx, `concat("a", "b") == ab`))
+	})
+
+	T.Run("References", func(T *testing.T) {
+		cond, err := params.ParseCondition("#b.response.x == 1 || #a.response.y == #b.response.z")
+		assert.Nil(T, err)
+		assert.Equal(T, []string{"a", "b"}, cond.References())
+	})
+
+	T.Run("Explain", func(T *testing.T) {
+		cond, err := params.ParseCondition(`#getPayment.response.amount == 99 && #getPayment.response.paid || #getUser.response.id != ""`)
+		assert.Nil(T, err)
+		assert.Equal(T, `120 == 99 && false || ? != ""`, cond.Explain(ctx))
+	})
+
+	T.Run("Syntax errors", func(T *testing.T) {
+		for _, s := range []string{"", "a ==", "a == b c", `"unterminated == a`} {
+			_, err := params.ParseCondition(s)
+			assert.NotNil(T, err, s)
+		}
+	})
+
+	T.Run("Unavailable response", func(T *testing.T) {
+		ctx.Results["skipped"] = &contract.OperationResult{}
+
+		cond, err := params.ParseCondition("#skipped.response.status == ok")
+		assert.Nil(T, err)
+
+		_, err = cond.Eval(ctx)
+		assert.NotNil(T, err)
+	})
+}
@@ -1,6 +1,7 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 )
@@ -40,6 +41,14 @@ func Cast(v interface{}) string {
 		return "false"
 	}
 
+	// Objects & arrays from JSON documents are encoded back to JSON,
+	// so they can be compared as a whole.
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
 	//TODO: this is very questionable :/
 	return fmt.Sprintf("%#v", v)
 }
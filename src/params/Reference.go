@@ -16,6 +16,8 @@ import (
 // has a value like "operationID.response.[0].user.id" this means that
 // the actual value comes from JSON response of the operation "operationID"
 // and it's exact location is "[0].user.id" field.
+// XML responses are supported too, their elements & attributes
+// are accessed the same way as JSON object fields.
 type Reference struct {
 	OpID     string
 	Result   *contract.OperationResult
@@ -25,33 +27,12 @@ type Reference struct {
 }
 
 // Value returns a parameter access function which computes and returns a real value.
+// The referenced response is decoded only once and shared by all references to it.
 func (pr Reference) Value() contract.ParameterAccess {
 	return func() string {
 		access, _ := ParseSelector(pr.Selector, pr.Log)
 
-		var data interface{}
-		var err error
-
-		if res, err := test.TryJSONObjectResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			return pr.Cast(access(res, pr.Log))
-		}
-
-		if res, err := test.TryJSONArrayResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			return pr.Cast(access(res, pr.Log))
-		}
-
-		if res, err := test.TryJSONStringResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
-		}
-
-		if res, err := test.TryJSONNumberResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
-		}
-
-		if res, err := test.TryJSONBooleanResponse(&pr.Result.ResponseBytes, pr.Log); err == nil {
-			data = res
-		}
-
+		data, err := test.Document(pr.Result)
 		if err != nil {
 			pr.Log.Error(err)
 		}
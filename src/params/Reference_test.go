@@ -3,6 +3,7 @@ package params_test
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -187,6 +188,12 @@ func Test_Reference(T *testing.T) {
 		assert.Equal(T, "map[string]string{\"foo\":\"F00\"}", ref.Cast(map[string]string{"foo": "F00"}))
 	})
 
+	T.Run("Cast/JSON", func(T *testing.T) {
+		ref := params.Reference{}
+		assert.Equal(T, `{"id":5,"tags":["a","b"]}`, ref.Cast(map[string]interface{}{"id": 5.0, "tags": []interface{}{"a", "b"}}))
+		assert.Equal(T, `[1,{"x":true}]`, ref.Cast([]interface{}{1.0, map[string]interface{}{"x": true}}))
+	})
+
 	T.Run("Value/Array", func(T *testing.T) {
 		ref := params.Reference{
 			Result: &contract.OperationResult{
@@ -214,6 +221,29 @@ func Test_Reference(T *testing.T) {
 		assert.Equal(T, "B4R", ref.Value()())
 	})
 
+	T.Run("Value/XML", func(T *testing.T) {
+		ref := params.Reference{
+			Result: &contract.OperationResult{
+				HTTPResponse: &http.Response{
+					Header: http.Header{
+						"Content-Type": []string{"application/xml"},
+					},
+				},
+				ResponseBytes: []byte(`<pets>
+					<Pet id="1"><name>doggie</name></Pet>
+					<Pet id="2"><name>kitty</name></Pet>
+				</pets>`),
+			},
+			Selector: "[1].name",
+			Log:      log.NewPlain(0),
+		}
+
+		assert.Equal(T, "kitty", ref.Value()())
+
+		ref.Selector = "[0].id"
+		assert.Equal(T, "1", ref.Value()())
+	})
+
 	T.Run("Value/String", func(T *testing.T) {
 		ref := params.Reference{
 			Result: &contract.OperationResult{
@@ -0,0 +1,356 @@
+package params
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// Template expression kinds.
+const (
+	TemplateLiteral = iota
+	TemplateReference
+	TemplateCall
+)
+
+var (
+	templateRefRx    = regexp.MustCompile(`^#(\w+(?:\.\w+)*?)\.response((?:\[\d+\]|\.[a-zA-Z_]+)*)`)
+	templateNumberRx = regexp.MustCompile(`^-?\d+(\.\d+)?`)
+	templateIdentRx  = regexp.MustCompile(`^[a-zA-Z_]\w*`)
+)
+
+// Template is a parameter value with embedded {{ expressions }}, like
+// "Bearer {{#login.response.token}}" or "user-{{randomString(8)}}@example.com".
+// Expressions are string & number literals, references to operation
+// responses and function calls, see TemplateFunctions.
+type Template struct {
+	Source string
+	Parts  []*TemplateExpr
+}
+
+// TemplateExpr is a single template expression. Value is a literal value,
+// a function name or a referenced operation.
+type TemplateExpr struct {
+	Kind     int
+	Value    string
+	Selector string
+	Args     []*TemplateExpr
+}
+
+// TemplateContext provides the referenced operation results
+// to templates, by script node names.
+// Seed makes the random values reproducible: every template execution
+// gets it's own generator seeded from Seed & Key, which names the place
+// of the template, like "getPet/body/name", so the values don't depend
+// on the order the templates are executed in.
+type TemplateContext struct {
+	Results map[string]*contract.OperationResult
+	Log     contract.Logger
+	Seed    *int64
+	Key     string
+
+	rnd *Random
+}
+
+// NewTemplateContext creates a new TemplateContext instance.
+func NewTemplateContext(log contract.Logger) *TemplateContext {
+	return &TemplateContext{
+		Results: map[string]*contract.OperationResult{},
+		Log:     log,
+	}
+}
+
+// With returns a copy of the context with the key appended to it's own one.
+// The copy shares the referenced results.
+func (ctx *TemplateContext) With(key string) *TemplateContext {
+	res := *ctx
+	if res.Key != "" {
+		key = res.Key + "/" + key
+	}
+
+	res.Key = key
+
+	return &res
+}
+
+// Random returns a new generator seeded from the context's seed & key,
+// or the shared unseeded one when there is no seed.
+func (ctx *TemplateContext) Random() *Random {
+	if ctx.Seed == nil {
+		return random
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(ctx.Key))
+
+	return NewRandom(*ctx.Seed ^ int64(h.Sum64()))
+}
+
+// execution returns a copy of the context with it's own generator,
+// shared by all the expressions of a single template or condition.
+func (ctx *TemplateContext) execution() *TemplateContext {
+	res := *ctx
+	res.rnd = ctx.Random()
+
+	return &res
+}
+
+// IsTemplate tells whether v contains template expressions.
+func IsTemplate(v string) bool {
+	return strings.Contains(v, "{{")
+}
+
+// ParseTemplate parses a template string.
+func ParseTemplate(s string) (*Template, error) {
+	tpl := &Template{Source: s}
+	rest := s
+
+	for len(rest) > 0 {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			tpl.Parts = append(tpl.Parts, &TemplateExpr{Kind: TemplateLiteral, Value: rest})
+			break
+		}
+
+		if start > 0 {
+			tpl.Parts = append(tpl.Parts, &TemplateExpr{Kind: TemplateLiteral, Value: rest[:start]})
+		}
+
+		expr, n, err := parseTemplateExpr(rest[start+2:])
+		if err != nil {
+			return nil, errors.Oops(fmt.Sprintf("Failed to parse the template '%s'.", s), err)
+		}
+
+		rest = strings.TrimLeft(rest[start+2+n:], " ")
+		if !strings.HasPrefix(rest, "}}") {
+			return nil, errors.Oops(fmt.Sprintf("Failed to parse the template '%s', expected }}.", s), nil)
+		}
+
+		rest = rest[2:]
+		tpl.Parts = append(tpl.Parts, expr)
+	}
+
+	return tpl, nil
+}
+
+// parseTemplateExpr parses a single expression in the beginning of s.
+// It returns the expression and number of parsed characters.
+func parseTemplateExpr(s string) (*TemplateExpr, int, error) {
+	trimmed := strings.TrimLeft(s, " ")
+	n := len(s) - len(trimmed)
+	s = trimmed
+
+	if s == "" {
+		return nil, 0, fmt.Errorf("unexpected end of the expression")
+	}
+
+	switch {
+	case s[0] == '"' || s[0] == '\'':
+		for i := 1; i < len(s); i++ {
+			if s[i] == s[0] {
+				return &TemplateExpr{Kind: TemplateLiteral, Value: s[1:i]}, n + i + 1, nil
+			}
+		}
+
+		return nil, 0, fmt.Errorf("unterminated string %s", s)
+
+	case s[0] == '#':
+		m := templateRefRx.FindStringSubmatch(s)
+		if m == nil {
+			return nil, 0, fmt.Errorf("invalid reference %s", s)
+		}
+
+		return &TemplateExpr{Kind: TemplateReference, Value: m[1], Selector: m[2]}, n + len(m[0]), nil
+
+	case templateNumberRx.MatchString(s):
+		m := templateNumberRx.FindString(s)
+		return &TemplateExpr{Kind: TemplateLiteral, Value: m}, n + len(m), nil
+
+	case templateIdentRx.MatchString(s):
+		name := templateIdentRx.FindString(s)
+		expr := &TemplateExpr{Kind: TemplateCall, Value: name}
+		i := len(name)
+
+		if i >= len(s) || s[i] != '(' {
+			return nil, 0, fmt.Errorf("expected ( after %s", name)
+		}
+
+		i++
+
+		for {
+			rest := strings.TrimLeft(s[i:], " ")
+			i = len(s) - len(rest)
+
+			if strings.HasPrefix(rest, ")") {
+				return expr, n + i + 1, nil
+			}
+
+			if len(expr.Args) > 0 {
+				if !strings.HasPrefix(rest, ",") {
+					return nil, 0, fmt.Errorf("expected , or ) in the %s arguments", name)
+				}
+
+				i++
+			}
+
+			arg, an, err := parseTemplateExpr(s[i:])
+			if err != nil {
+				return nil, 0, err
+			}
+
+			expr.Args = append(expr.Args, arg)
+			i += an
+		}
+	}
+
+	return nil, 0, fmt.Errorf("unexpected %s", s)
+}
+
+// References returns a sorted list of the operations referenced in the template.
+func (tpl *Template) References() []string {
+	refs := map[string]bool{}
+
+	var walk func(exprs []*TemplateExpr)
+	walk = func(exprs []*TemplateExpr) {
+		for _, expr := range exprs {
+			if expr.Kind == TemplateReference {
+				refs[expr.Value] = true
+			}
+
+			walk(expr.Args)
+		}
+	}
+
+	walk(tpl.Parts)
+
+	res := []string{}
+	for ref := range refs {
+		res = append(res, ref)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// Execute computes the template value.
+func (tpl *Template) Execute(ctx *TemplateContext) (string, error) {
+	ctx = ctx.execution()
+	res := ""
+
+	for _, expr := range tpl.Parts {
+		v, err := expr.Eval(ctx)
+		if err != nil {
+			return "", errors.Oops(fmt.Sprintf("Failed to execute the template '%s'.", tpl.Source), err)
+		}
+
+		res += v
+	}
+
+	return res, nil
+}
+
+// Value returns a parameter access function which executes the template.
+func (tpl *Template) Value(ctx *TemplateContext) contract.ParameterAccess {
+	return func() string {
+		v, err := tpl.Execute(ctx)
+		if err != nil {
+			ctx.Log.Error(err)
+		}
+
+		return v
+	}
+}
+
+// Eval computes the expression value.
+func (expr *TemplateExpr) Eval(ctx *TemplateContext) (string, error) {
+	switch expr.Kind {
+	case TemplateReference:
+		result := ctx.Results[expr.Value]
+		if result == nil || (result.HTTPResponse == nil && result.Size() == 0) {
+			return "", fmt.Errorf("the #%s operation response is not available", expr.Value)
+		}
+
+		return Reference{
+			OpID:     expr.Value,
+			Result:   result,
+			Selector: expr.Selector,
+			Log:      ctx.Log,
+		}.Value()(), nil
+
+	case TemplateCall:
+		fn, ok := TemplateFunctions[expr.Value]
+		if !ok {
+			return "", fmt.Errorf("unknown function %s()", expr.Value)
+		}
+
+		args := []string{}
+		for _, arg := range expr.Args {
+			v, err := arg.Eval(ctx)
+			if err != nil {
+				return "", err
+			}
+
+			args = append(args, v)
+		}
+
+		rnd := ctx.rnd
+		if rnd == nil {
+			rnd = ctx.Random()
+		}
+
+		v, err := fn(rnd, args...)
+		if err != nil {
+			return "", fmt.Errorf("%s(): %s", expr.Value, err.Error())
+		}
+
+		return v, nil
+	}
+
+	return expr.Value, nil
+}
+
+// TemplateSource is a parameter source which treats the values
+// of another source as templates.
+type TemplateSource struct {
+	Source  contract.ParameterSource
+	Context *TemplateContext
+}
+
+// Templates creates a new TemplateSource instance.
+func Templates(src contract.ParameterSource, ctx *TemplateContext) *TemplateSource {
+	return &TemplateSource{
+		Source:  src,
+		Context: ctx,
+	}
+}
+
+// Iterate creates an iterable channel. Values which fail to parse
+// as templates are reported and used as is.
+func (src *TemplateSource) Iterate() contract.ParameterIterator {
+	ch := make(contract.ParameterIterator)
+
+	go func() {
+		for p := range src.Source.Iterate() {
+			if v := p.V(); IsTemplate(v) {
+				tpl, err := ParseTemplate(v)
+				if err != nil {
+					src.Context.Log.Error(err)
+				} else {
+					p.V = tpl.Value(src.Context.With(p.N))
+				}
+			}
+
+			ch <- p
+		}
+
+		close(ch)
+	}()
+
+	return ch
+}
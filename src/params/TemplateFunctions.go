@@ -0,0 +1,266 @@
+package params
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TemplateFunction is a function available in templates,
+// rnd is the source of randomness for the random values.
+type TemplateFunction func(rnd *Random, args ...string) (string, error)
+
+// TemplateFunctions are the functions available in templates.
+var TemplateFunctions = map[string]TemplateFunction{
+	"uuid":         UUID,
+	"now":          Now,
+	"randomInt":    RandomInt,
+	"randomString": RandomString,
+	"base64":       Base64,
+	"sha256":       SHA256,
+	"urlencode":    URLEncode,
+	"concat":       Concat,
+	"sum":          Sum,
+	"count":        Count,
+}
+
+// Random is a source of randomness for templates, safe for concurrent use.
+type Random struct {
+	sync.Mutex
+	*rand.Rand
+}
+
+// NewRandom creates a new Random instance.
+func NewRandom(seed int64) *Random {
+	return &Random{
+		Rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// random is the source of randomness for the unseeded templates.
+var random = NewRandom(time.Now().UnixNano())
+
+// templateArgs checks the number of template function arguments.
+func templateArgs(args []string, min int, max int) error {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return fmt.Errorf("expected %d argument(s), got %d", min, len(args))
+		}
+
+		return fmt.Errorf("expected %d to %d arguments, got %d", min, max, len(args))
+	}
+
+	return nil
+}
+
+// UUID returns a random version 4 UUID.
+func UUID(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 0, 0); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+
+	rnd.Lock()
+	rnd.Read(b)
+	rnd.Unlock()
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Now returns the current time in the given format, RFC3339 by default.
+// The format is either a Go time layout, or one of "unix", "unixms" & "rfc3339".
+// The offset is a duration like "-24h" or "90m" added to the current time.
+func Now(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 0, 2); err != nil {
+		return "", err
+	}
+
+	t := time.Now()
+
+	if len(args) > 1 && args[1] != "" {
+		offset, err := time.ParseDuration(args[1])
+		if err != nil {
+			return "", err
+		}
+
+		t = t.Add(offset)
+	}
+
+	format := "rfc3339"
+	if len(args) > 0 && args[0] != "" {
+		format = args[0]
+	}
+
+	switch format {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+
+	case "unixms":
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), nil
+
+	case "rfc3339":
+		return t.Format(time.RFC3339), nil
+	}
+
+	return t.Format(format), nil
+}
+
+// RandomInt returns a random integer in the [a, b] range.
+func RandomInt(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 2, 2); err != nil {
+		return "", err
+	}
+
+	a, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", err
+	}
+
+	if b < a {
+		return "", fmt.Errorf("%d is less than %d", b, a)
+	}
+
+	rnd.Lock()
+	defer rnd.Unlock()
+
+	return strconv.FormatInt(a+rnd.Int63n(b-a+1), 10), nil
+}
+
+// RandomString returns a random alphanumeric string of length n.
+func RandomString(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 1); err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return "", err
+	}
+
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	rnd.Lock()
+	defer rnd.Unlock()
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+
+	return string(b), nil
+}
+
+// Base64 returns the standard base64 encoding of the argument.
+func Base64(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 1); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(args[0])), nil
+}
+
+// SHA256 returns a hex-encoded SHA-256 checksum of the argument.
+func SHA256(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 1); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(args[0]))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// URLEncode escapes the argument to be used in URL queries.
+func URLEncode(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 1); err != nil {
+		return "", err
+	}
+
+	return url.QueryEscape(args[0]), nil
+}
+
+// Concat concatenates the arguments.
+func Concat(rnd *Random, args ...string) (string, error) {
+	return strings.Join(args, ""), nil
+}
+
+// templateArray decodes a JSON array argument, taking the field
+// of every element when it's given.
+func templateArray(args []string) ([]interface{}, error) {
+	items := []interface{}{}
+	if err := json.Unmarshal([]byte(args[0]), &items); err != nil {
+		return nil, fmt.Errorf("%s is not an array", args[0])
+	}
+
+	if len(args) < 2 {
+		return items, nil
+	}
+
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the item %d is not an object", i)
+		}
+
+		items[i] = obj[args[1]]
+	}
+
+	return items, nil
+}
+
+// Sum returns the sum of the numbers in a JSON array, or of the field
+// of the objects in it, like sum(#getOrder.response.items, "price").
+// The sum is rounded to 9 decimal places to drop the floating point noise.
+func Sum(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 2); err != nil {
+		return "", err
+	}
+
+	items, err := templateArray(args)
+	if err != nil {
+		return "", err
+	}
+
+	sum := 0.0
+	for i, item := range items {
+		v, err := strconv.ParseFloat(Cast(item), 64)
+		if err != nil {
+			return "", fmt.Errorf("the item %d is not a number", i)
+		}
+
+		sum += v
+	}
+
+	return strconv.FormatFloat(math.Round(sum*1e9)/1e9, 'f', -1, 64), nil
+}
+
+// Count returns the number of items in a JSON array.
+func Count(rnd *Random, args ...string) (string, error) {
+	if err := templateArgs(args, 1, 1); err != nil {
+		return "", err
+	}
+
+	items, err := templateArray(args)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(len(items)), nil
+}
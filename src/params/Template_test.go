@@ -0,0 +1,142 @@
+package params_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+func execTemplate(T *testing.T, ctx *params.TemplateContext, s string) string {
+	tpl, err := params.ParseTemplate(s)
+	assert.Nil(T, err)
+
+	v, err := tpl.Execute(ctx)
+	assert.Nil(T, err)
+
+	return v
+}
+
+func Test_Template(T *testing.T) {
+	ctx := params.NewTemplateContext(log.NewPlain(0))
+	ctx.Results["login"] = &contract.OperationResult{
+		ResponseBytes: []byte(`{"token": "T0K3N", "user": {"name": "gopher"}}`),
+	}
+
+	T.Run("Literal", func(T *testing.T) {
+		assert.False(T, params.IsTemplate("#login.response.token"))
+		assert.Equal(T, "no templates", execTemplate(T, ctx, "no templates"))
+	})
+
+	T.Run("Reference", func(T *testing.T) {
+		tpl, err := params.ParseTemplate("Bearer {{#login.response.token}} for {{ #login.response.user.name }}")
+		assert.Nil(T, err)
+		assert.Equal(T, []string{"login"}, tpl.References())
+
+		v, err := tpl.Execute(ctx)
+		assert.Nil(T, err)
+		assert.Equal(T, "Bearer T0K3N for gopher", v)
+	})
+
+	T.Run("Namespaced reference", func(T *testing.T) {
+		tpl, err := params.ParseTemplate("{{#auth.login.response.token}}")
+		assert.Nil(T, err)
+		assert.Equal(T, []string{"auth.login"}, tpl.References())
+	})
+
+	T.Run("Functions", func(T *testing.T) {
+		assert.Equal(T, "Z29waGVy", execTemplate(T, ctx, `{{base64("gopher")}}`))
+		assert.Equal(T, "a%2Bb+c", execTemplate(T, ctx, `{{urlencode('a+b c')}}`))
+		assert.Equal(T, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", execTemplate(T, ctx, `{{sha256("hello")}}`))
+		assert.Equal(T, "user-42-T0K3N", execTemplate(T, ctx, `{{concat("user-", 42, "-", #login.response.token)}}`))
+		assert.Equal(T, "Z29waGVy:VDBLM04=", execTemplate(T, ctx, `{{base64(#login.response.user.name)}}:{{base64(#login.response.token)}}`))
+		assert.Regexp(T, regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`), execTemplate(T, ctx, `{{now("2006-01-02", "-24h")}}`))
+		assert.Regexp(T, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), execTemplate(T, ctx, `{{uuid()}}`))
+		assert.Regexp(T, regexp.MustCompile(`^[a-zA-Z0-9]{12}$`), execTemplate(T, ctx, `{{randomString(12)}}`))
+		assert.Contains(T, []string{"1", "2", "3"}, execTemplate(T, ctx, `{{randomInt(1, 3)}}`))
+	})
+
+	T.Run("Aggregates", func(T *testing.T) {
+		ctx := params.NewTemplateContext(log.NewPlain(0))
+		ctx.Results["order"] = &contract.OperationResult{
+			ResponseBytes: []byte(`{"items": [{"price": 0.1}, {"price": 0.2}, {"price": "2"}], "ids": [3, 4]}`),
+		}
+
+		assert.Equal(T, "2.3", execTemplate(T, ctx, `{{sum(#order.response.items, "price")}}`))
+		assert.Equal(T, "7", execTemplate(T, ctx, `{{sum(#order.response.ids)}}`))
+		assert.Equal(T, "3", execTemplate(T, ctx, `{{count(#order.response.items)}}`))
+
+		for _, s := range []string{
+			`{{sum(#order.response.items)}}`,
+			`{{sum(#order.response.ids, "price")}}`,
+			`{{count("nope")}}`,
+		} {
+			tpl, err := params.ParseTemplate(s)
+			assert.Nil(T, err)
+
+			_, err = tpl.Execute(ctx)
+			assert.NotNil(T, err, s)
+		}
+	})
+
+	T.Run("Seed", func(T *testing.T) {
+		s := `{{uuid()}} {{randomInt(0, 1000000)}} {{randomString(16)}}`
+
+		seed := int64(42)
+		seeded := params.NewTemplateContext(log.NewPlain(0))
+		seeded.Seed = &seed
+
+		v1 := execTemplate(T, seeded.With("getPet/body/name"), s)
+		execTemplate(T, seeded.With("getUser/body/name"), s)
+		v2 := execTemplate(T, seeded.With("getPet/body/name"), s)
+
+		assert.Equal(T, v1, v2)
+		assert.NotEqual(T, v1, execTemplate(T, seeded.With("getUser/body/name"), s))
+		assert.NotEqual(T, v1, execTemplate(T, ctx.With("getPet/body/name"), s))
+	})
+
+	T.Run("Parse errors", func(T *testing.T) {
+		for _, s := range []string{
+			"{{uuid()",
+			"{{uuid}}",
+			`{{concat("a" "b")}}`,
+			`{{"unterminated}}`,
+			"{{#login.token}}",
+			"{{}}",
+		} {
+			_, err := params.ParseTemplate(s)
+			assert.NotNil(T, err, s)
+		}
+	})
+
+	T.Run("Execution errors", func(T *testing.T) {
+		for _, s := range []string{
+			"{{nope()}}",
+			"{{randomInt(1)}}",
+			"{{randomInt(5, 1)}}",
+			"{{#other.response.id}}",
+		} {
+			tpl, err := params.ParseTemplate(s)
+			assert.Nil(T, err, s)
+
+			_, err = tpl.Execute(ctx)
+			assert.NotNil(T, err, s)
+		}
+	})
+
+	T.Run("TemplateSource", func(T *testing.T) {
+		src := params.NewMemorySource("test")
+		src.Add("auth", "Bearer {{#login.response.token}}")
+		src.Add("plain", "value")
+
+		values := map[string]string{}
+		for p := range params.Templates(src, ctx).Iterate() {
+			values[p.N] = p.V()
+		}
+
+		assert.Equal(T, map[string]string{"auth": "Bearer T0K3N", "plain": "value"}, values)
+	})
+}
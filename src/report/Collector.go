@@ -0,0 +1,186 @@
+package report
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// Case is a single test case, an operation in manual mode or a script node.
+type Case struct {
+	Name      string
+	ClassName string
+	Start     time.Time
+	Duration  time.Duration
+	Done      bool
+	Success   bool
+	Failures  []string
+	Errors    []string
+	Output    []string
+
+	// SkipReason is set for the script nodes which have not been executed.
+	SkipReason string
+
+	// Teardown is set for the cases of the script teardown operations.
+	Teardown bool
+
+	Parameters   []Parameter
+	SchemaErrors []SchemaError
+	Result       *contract.OperationResult
+}
+
+// Parameter is a request parameter used in a test case.
+type Parameter struct {
+	In     string
+	Name   string
+	Value  string
+	Source string
+}
+
+// SchemaError is a schema validation error. Field is a JSON path
+// to the failing value, like "tags.0.name", or "(root)".
+type SchemaError struct {
+	Schema      string
+	Field       string
+	Description string
+}
+
+// Fail adds a failure message to the case.
+func (c *Case) Fail(msg string) {
+	c.Failures = append(c.Failures, msg)
+}
+
+// End finishes the case.
+func (c *Case) End(success bool) {
+	c.Duration = time.Since(c.Start)
+	c.Success = success
+	c.Done = true
+
+	if !success && len(c.Failures) == 0 && len(c.Errors) == 0 {
+		c.Fail("The operation has failed.")
+	}
+}
+
+// Collector collects test cases from multiple loggers.
+// It is safe for concurrent use.
+type Collector struct {
+	Name  string
+	Start time.Time
+
+	// Nodes & Edges describe the script execution graph, if any.
+	// An edge from A to B means that A depends on B.
+	Nodes []string
+	Edges [][2]string
+
+	mutex    sync.Mutex
+	cases    []*Case
+	scripts  []*Collector
+	teardown bool
+}
+
+// NewCollector creates a new Collector instance.
+func NewCollector() *Collector {
+	return &Collector{
+		Name:  "oasis",
+		Start: time.Now(),
+	}
+}
+
+// Begin starts a new test case.
+func (collector *Collector) Begin(name string, className string) *Case {
+	c := &Case{
+		Name:      name,
+		ClassName: className,
+		Start:     time.Now(),
+	}
+
+	collector.mutex.Lock()
+	c.Teardown = collector.teardown
+	collector.cases = append(collector.cases, c)
+	collector.mutex.Unlock()
+
+	return c
+}
+
+// TearingDown makes all the cases begun afterwards teardown ones.
+func (collector *Collector) TearingDown() {
+	collector.mutex.Lock()
+	collector.teardown = true
+	collector.mutex.Unlock()
+}
+
+// Script creates a collector for a script executed in a suite.
+func (collector *Collector) Script(name string) *Collector {
+	script := NewCollector()
+	script.Name = name
+
+	collector.mutex.Lock()
+	collector.scripts = append(collector.scripts, script)
+	collector.mutex.Unlock()
+
+	return script
+}
+
+// Scripts returns a list of the suite script collectors sorted by their names.
+func (collector *Collector) Scripts() []*Collector {
+	collector.mutex.Lock()
+	scripts := append([]*Collector{}, collector.scripts...)
+	collector.mutex.Unlock()
+
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].Name < scripts[j].Name
+	})
+
+	return scripts
+}
+
+// Cases returns a list of the collected test cases in order of their start.
+func (collector *Collector) Cases() []*Case {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	return append([]*Case{}, collector.cases...)
+}
+
+// SetGraph stores the script execution graph nodes & edges.
+func (collector *Collector) SetGraph(graph gcontract.Graph) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.Nodes = []string{}
+	collector.Edges = [][2]string{}
+
+	for n := range graph.Nodes().Range() {
+		collector.Nodes = append(collector.Nodes, string(n.ID()))
+
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			collector.Edges = append(collector.Edges, [2]string{string(n.ID()), string(an.ID())})
+		}
+	}
+
+	sort.Strings(collector.Nodes)
+	sort.Slice(collector.Edges, func(i, j int) bool {
+		if collector.Edges[i][0] != collector.Edges[j][0] {
+			return collector.Edges[i][0] < collector.Edges[j][0]
+		}
+
+		return collector.Edges[i][1] < collector.Edges[j][1]
+	})
+}
+
+// Case returns the last case with the given name, or nil.
+func (collector *Collector) Case(name string) *Case {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	for i := len(collector.cases) - 1; i >= 0; i-- {
+		if collector.cases[i].Name == name {
+			return collector.cases[i]
+		}
+	}
+
+	return nil
+}
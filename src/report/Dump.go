@@ -0,0 +1,68 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+// BodyPreviewLimit is the maximum number of response body bytes
+// included into reports.
+var BodyPreviewLimit = 64 * 1024
+
+// Dump creates a textual representation of an HTTP exchange
+// with the request & response headers and the response body.
+// Only textual bodies are included, up to BodyPreviewLimit bytes.
+func Dump(result *contract.OperationResult) string {
+	lines := []string{}
+
+	dumpHeaders := func(headers map[string][]string) {
+		names := []string{}
+		for name := range headers {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			for _, v := range headers[name] {
+				lines = append(lines, name+": "+log.MaskHeader(name, v))
+			}
+		}
+	}
+
+	if req := result.HTTPRequest; req != nil {
+		lines = append(lines, req.Method+" "+req.URL.String())
+		dumpHeaders(req.Header)
+		lines = append(lines, "")
+	}
+
+	if resp := result.HTTPResponse; resp != nil {
+		lines = append(lines, resp.Proto+" "+resp.Status)
+		dumpHeaders(resp.Header)
+		lines = append(lines, "")
+
+		CT := api.ParseMediaType(resp.Header.Get("Content-Type"))
+
+		switch {
+		case result.ResponseFile != "":
+			lines = append(lines, fmt.Sprintf("(%d bytes stored in %s)", result.Size(), result.ResponseFile))
+
+		case CT.IsBinary():
+			lines = append(lines, fmt.Sprintf("(%d bytes of %s)", result.Size(), CT.Essence()))
+
+		case len(result.ResponseBytes) > BodyPreviewLimit:
+			lines = append(lines, string(result.ResponseBytes[:BodyPreviewLimit]))
+			lines = append(lines, fmt.Sprintf("(%d more bytes)", len(result.ResponseBytes)-BodyPreviewLimit))
+
+		default:
+			lines = append(lines, string(result.ResponseBytes))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,432 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// HTMLReport is the data of an HTML report.
+type HTMLReport struct {
+	Title     string
+	Generated string
+	Duration  string
+	Passed    int
+	Failed    int
+	Skipped   int
+
+	// TeardownFailed counts the failed teardown cases,
+	// which are not counted as the test results.
+	TeardownFailed int
+
+	Graph template.HTML
+	Cases []HTMLCase
+}
+
+// HTMLCase is the data of a single test case in an HTML report.
+type HTMLCase struct {
+	Anchor       string
+	Name         string
+	ClassName    string
+	Status       string
+	Duration     string
+	Failures     []string
+	Errors       []string
+	Parameters   []Parameter
+	SchemaErrors []SchemaError
+	Teardown     bool
+	Request      string
+	Response     string
+	Body         template.HTML
+}
+
+// HTML writes the collected cases as a single self-contained HTML file.
+// The cases of the suite scripts have the script names prefixed to their names.
+func HTML(collector *Collector, w io.Writer) error {
+	rep := HTMLReport{
+		Title:     collector.Name,
+		Generated: time.Now().Format(time.RFC1123),
+		Duration:  seconds(time.Since(collector.Start)) + "s",
+	}
+
+	anchors := map[string]string{}
+	statuses := map[string]string{}
+
+	cases := collector.Cases()
+	for _, script := range collector.Scripts() {
+		for _, c := range script.Cases() {
+			sc := *c
+			sc.Name = script.Name + ": " + c.Name
+			cases = append(cases, &sc)
+		}
+	}
+
+	for i, c := range cases {
+		hc := HTMLCase{
+			Anchor:       "case-" + strconv.Itoa(i),
+			Name:         c.Name,
+			ClassName:    c.ClassName,
+			Status:       CaseStatus(c),
+			Duration:     seconds(c.Duration) + "s",
+			Failures:     c.Failures,
+			Errors:       c.Errors,
+			Parameters:   c.Parameters,
+			SchemaErrors: c.SchemaErrors,
+			Teardown:     c.Teardown,
+		}
+
+		if c.Result != nil {
+			hc.Request, hc.Response = HTMLExchange(c.Result)
+			hc.Body = HTMLBody(c.Result, c.SchemaErrors)
+		}
+
+		switch {
+		case hc.Teardown:
+			if hc.Status == "failed" {
+				rep.TeardownFailed++
+			}
+
+		case hc.Status == "passed":
+			rep.Passed++
+		case hc.Status == "failed":
+			rep.Failed++
+		default:
+			rep.Skipped++
+		}
+
+		anchors[c.Name] = hc.Anchor
+		statuses[c.Name] = hc.Status
+
+		rep.Cases = append(rep.Cases, hc)
+	}
+
+	if len(collector.Nodes) > 0 {
+		rep.Graph = HTMLGraph(collector.Nodes, collector.Edges, anchors, statuses)
+	}
+
+	return htmlTemplate.Execute(w, rep)
+}
+
+// CaseStatus returns "passed", "failed" or "skipped".
+func CaseStatus(c *Case) string {
+	if !c.Done {
+		return "skipped"
+	}
+
+	if c.Success {
+		return "passed"
+	}
+
+	return "failed"
+}
+
+// HTMLExchange renders request & response lines and headers.
+func HTMLExchange(result *contract.OperationResult) (string, string) {
+	headers := func(h map[string][]string) string {
+		names := []string{}
+		for name := range h {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		lines := []string{}
+		for _, name := range names {
+			for _, v := range h[name] {
+				lines = append(lines, name+": "+log.MaskHeader(name, v))
+			}
+		}
+
+		return strings.Join(lines, "\n")
+	}
+
+	req, resp := "", ""
+
+	if result.HTTPRequest != nil {
+		req = result.HTTPRequest.Method + " " + result.HTTPRequest.URL.String() + "\n" + headers(result.HTTPRequest.Header)
+	}
+
+	if result.HTTPResponse != nil {
+		resp = result.HTTPResponse.Proto + " " + result.HTTPResponse.Status + "\n" + headers(result.HTTPResponse.Header)
+	}
+
+	return req, resp
+}
+
+// HTMLBody renders a response body. JSON bodies are pretty-printed
+// with the values at failing schema paths highlighted.
+func HTMLBody(result *contract.OperationResult, schemaErrors []SchemaError) template.HTML {
+	if result.HTTPResponse == nil || result.Size() == 0 {
+		return ""
+	}
+
+	CT := api.ParseMediaType(result.HTTPResponse.Header.Get("Content-Type"))
+
+	if CT.IsJSON() || CT.IsXML() {
+		if doc, err := test.Document(result); err == nil {
+			fails := map[string][]string{}
+			for _, se := range schemaErrors {
+				fails[se.Field] = append(fails[se.Field], se.Description)
+			}
+
+			b := &strings.Builder{}
+			HTMLJSON(b, doc, "(root)", "", fails)
+
+			return template.HTML(b.String())
+		}
+	}
+
+	if CT.IsBinary() || result.ResponseFile != "" {
+		return template.HTML(html.EscapeString(fmt.Sprintf("(%d bytes of %s)", result.Size(), CT.Essence())))
+	}
+
+	body := result.ResponseBytes
+	if len(body) > BodyPreviewLimit {
+		body = body[:BodyPreviewLimit]
+	}
+
+	return template.HTML(html.EscapeString(string(body)))
+}
+
+// HTMLJSON renders a JSON value as indented HTML. Values at paths
+// found in fails are highlighted, with error descriptions in the title.
+func HTMLJSON(b *strings.Builder, v interface{}, path string, indent string, fails map[string][]string) {
+	child := func(key string) string {
+		if path == "(root)" {
+			return key
+		}
+
+		return path + "." + key
+	}
+
+	open := func(p string) bool {
+		if descs, ok := fails[p]; ok {
+			b.WriteString(`<span class="fail" title="` + html.EscapeString(strings.Join(descs, "\n")) + `">`)
+			return true
+		}
+
+		return false
+	}
+
+	if path == "(root)" && open(path) {
+		defer b.WriteString("</span>")
+	}
+
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		keys := []string{}
+		for k := range vv {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		if len(keys) == 0 {
+			b.WriteString("{}")
+			return
+		}
+
+		b.WriteString("{\n")
+
+		for i, k := range keys {
+			b.WriteString(indent + "  ")
+
+			opened := open(child(k))
+			kj, _ := json.Marshal(k)
+			b.WriteString(`<span class="key">` + html.EscapeString(string(kj)) + "</span>: ")
+			HTMLJSON(b, vv[k], child(k), indent+"  ", fails)
+
+			if opened {
+				b.WriteString("</span>")
+			}
+
+			if i < len(keys)-1 {
+				b.WriteString(",")
+			}
+
+			b.WriteString("\n")
+		}
+
+		b.WriteString(indent + "}")
+
+	case []interface{}:
+		if len(vv) == 0 {
+			b.WriteString("[]")
+			return
+		}
+
+		b.WriteString("[\n")
+
+		for i, item := range vv {
+			b.WriteString(indent + "  ")
+
+			opened := open(child(strconv.Itoa(i)))
+			HTMLJSON(b, item, child(strconv.Itoa(i)), indent+"  ", fails)
+
+			if opened {
+				b.WriteString("</span>")
+			}
+
+			if i < len(vv)-1 {
+				b.WriteString(",")
+			}
+
+			b.WriteString("\n")
+		}
+
+		b.WriteString(indent + "]")
+
+	default:
+		vj, _ := json.Marshal(vv)
+		b.WriteString(`<span class="value">` + html.EscapeString(string(vj)) + "</span>")
+	}
+}
+
+// HTMLGraph renders the execution graph as an SVG image. Nodes are laid
+// out in columns by their dependency depth, so dependencies are on the left.
+func HTMLGraph(nodes []string, edges [][2]string, anchors map[string]string, statuses map[string]string) template.HTML {
+	deps := map[string][]string{}
+	for _, e := range edges {
+		deps[e[0]] = append(deps[e[0]], e[1])
+	}
+
+	depths := map[string]int{}
+	var depth func(n string, visiting map[string]bool) int
+	depth = func(n string, visiting map[string]bool) int {
+		if d, ok := depths[n]; ok {
+			return d
+		}
+
+		if visiting[n] {
+			return 0
+		}
+
+		visiting[n] = true
+		d := 0
+
+		for _, dep := range deps[n] {
+			if dd := depth(dep, visiting) + 1; dd > d {
+				d = dd
+			}
+		}
+
+		depths[n] = d
+		return d
+	}
+
+	columns := map[int][]string{}
+	maxDepth, maxRows := 0, 0
+
+	for _, n := range nodes {
+		d := depth(n, map[string]bool{})
+		columns[d] = append(columns[d], n)
+
+		if d > maxDepth {
+			maxDepth = d
+		}
+
+		if len(columns[d]) > maxRows {
+			maxRows = len(columns[d])
+		}
+	}
+
+	const w, h, dx, dy = 180, 36, 240, 56
+
+	type point struct{ x, y int }
+	pos := map[string]point{}
+
+	for d := 0; d <= maxDepth; d++ {
+		for row, n := range columns[d] {
+			pos[n] = point{20 + d*dx, 20 + row*dy}
+		}
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, 40+maxDepth*dx+w, 40+(maxRows-1)*dy+h)
+	b.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>`)
+
+	for _, e := range edges {
+		from, to := pos[e[1]], pos[e[0]]
+		fmt.Fprintf(b, `<line class="edge" x1="%d" y1="%d" x2="%d" y2="%d" marker-end="url(#arrow)"/>`, from.x+w, from.y+h/2, to.x, to.y+h/2)
+	}
+
+	for _, n := range nodes {
+		p := pos[n]
+		status := statuses[n]
+		if status == "" {
+			status = "skipped"
+		}
+
+		fmt.Fprintf(b, `<a href="#%s"><rect class="node %s" x="%d" y="%d" width="%d" height="%d" rx="4"/>`, anchors[n], status, p.x, p.y, w, h)
+		fmt.Fprintf(b, `<text x="%d" y="%d">%s</text></a>`, p.x+10, p.y+h/2+5, html.EscapeString(n))
+	}
+
+	b.WriteString("</svg>")
+
+	return template.HTML(b.String())
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - Oasis report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+h1 small { color: #888; font-weight: normal; font-size: 50%; }
+.summary span { margin-right: 1.5em; }
+.passed { color: #2a7; } .failed { color: #d33; } .skipped { color: #888; }
+rect.node { fill: #eee; stroke: #888; } rect.node.passed { fill: #dfd; stroke: #2a7; }
+rect.node.failed { fill: #fdd; stroke: #d33; } svg text { font-size: 13px; fill: #222; }
+line.edge { stroke: #888; stroke-width: 1.5; }
+.case { border: 1px solid #ddd; border-radius: 4px; margin: 1em 0; padding: 0 1em 1em; }
+.case h2 { font-size: 1.1em; } .case h2 small { color: #888; font-weight: normal; }
+table { border-collapse: collapse; } td, th { border: 1px solid #ddd; padding: 2px 8px; text-align: left; }
+pre { background: #f7f7f7; padding: 0.5em; overflow-x: auto; }
+pre .key { color: #05a; } pre .value { color: #a50; }
+pre .fail { background: #fdd; outline: 1px solid #d33; }
+ul.failures li { color: #d33; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} <small>{{.Generated}}, {{.Duration}}</small></h1>
+<p class="summary">
+<span class="passed">{{.Passed}} passed</span>
+<span class="failed">{{.Failed}} failed</span>
+<span class="skipped">{{.Skipped}} skipped</span>
+{{if .TeardownFailed}}<span class="failed">{{.TeardownFailed}} teardown failed</span>{{end}}
+</p>
+{{if .Graph}}<h2>Execution graph</h2>
+{{.Graph}}{{end}}
+{{range .Cases}}<div class="case" id="{{.Anchor}}">
+<h2><span class="{{.Status}}">{{.Status}}</span> {{.Name}} <small>{{if .Teardown}}teardown, {{end}}{{.ClassName}}, {{.Duration}}</small></h2>
+{{if or .Failures .Errors}}<ul class="failures">{{range .Failures}}<li>{{.}}</li>{{end}}{{range .Errors}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Parameters}}<h3>Parameters</h3>
+<table><tr><th>In</th><th>Name</th><th>Value</th><th>Source</th></tr>
+{{range .Parameters}}<tr><td>{{.In}}</td><td>{{.Name}}</td><td>{{.Value}}</td><td>{{.Source}}</td></tr>
+{{end}}</table>{{end}}
+{{if .SchemaErrors}}<h3>Schema errors</h3>
+<table><tr><th>Schema</th><th>Path</th><th>Error</th></tr>
+{{range .SchemaErrors}}<tr><td>{{.Schema}}</td><td>{{.Field}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Request}}<h3>Request</h3>
+<pre>{{.Request}}</pre>{{end}}
+{{if .Response}}<h3>Response</h3>
+<pre>{{.Response}}</pre>{{end}}
+{{if .Body}}<pre>{{.Body}}</pre>{{end}}
+</div>
+{{end}}
+</body>
+</html>
+`))
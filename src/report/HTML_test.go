@@ -0,0 +1,56 @@
+package report_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/report"
+)
+
+func Test_HTML(T *testing.T) {
+	collector := report.NewCollector()
+	collector.Name = "script.yaml"
+	collector.Nodes = []string{"create", "get"}
+	collector.Edges = [][2]string{{"get", "create"}}
+
+	create := collector.Begin("create", "POST /pet")
+	create.End(true)
+
+	get := collector.Begin("get", "GET /pet/{petId}")
+	get.Parameters = []report.Parameter{{In: "path", Name: "petId", Value: "42", Source: "create node"}}
+	get.SchemaErrors = []report.SchemaError{{Schema: "Pet", Field: "tags.0.name", Description: "Invalid type. Expected: string, given: integer"}}
+	get.Result = &contract.OperationResult{
+		HTTPRequest: &http.Request{
+			Method: "GET",
+			URL:    &url.URL{Scheme: "http", Host: "localhost", Path: "/pet/42"},
+			Header: http.Header{},
+		},
+		HTTPResponse: &http.Response{
+			Proto:  "HTTP/1.1",
+			Status: "200 OK",
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+		},
+		ResponseBytes: []byte(`{"id": 42, "tags": [{"name": 1}]}`),
+	}
+	get.Fail("Pet schema failure.")
+	get.End(false)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(T, report.HTML(collector, buf))
+
+	html := buf.String()
+
+	assert.Contains(T, html, "<svg")
+	assert.Contains(T, html, `<rect class="node failed"`)
+	assert.Contains(T, html, "1 passed")
+	assert.Contains(T, html, "1 failed")
+	assert.Contains(T, html, "<td>create node</td>")
+	assert.Contains(T, html, "GET http://localhost/pet/42")
+	assert.Contains(T, html, `<span class="fail" title="Invalid type. Expected: string, given: integer"><span class="key">&#34;name&#34;</span>: <span class="value">1</span></span>`)
+	assert.True(T, strings.HasPrefix(html, "<!DOCTYPE html>"))
+}
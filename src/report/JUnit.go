@@ -0,0 +1,142 @@
+package report
+
+import (
+	goxml "encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JUnitSuites is the root element of a JUnit XML report.
+type JUnitSuites struct {
+	XMLName goxml.Name   `xml:"testsuites"`
+	Suites  []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite is a JUnit test suite.
+type JUnitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a JUnit test case.
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure is a failure or an error of a JUnit test case.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a JUnit test case as skipped.
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit writes the collected cases as a JUnit XML report.
+// The script teardown cases make a separate suite,
+// every script of a suite makes it's own ones.
+func JUnit(collector *Collector, w io.Writer) error {
+	suites := JUnitSuites{}
+
+	if scripts := collector.Scripts(); len(scripts) > 0 {
+		for _, script := range scripts {
+			suites.Suites = append(suites.Suites, junitSuites(script)...)
+		}
+	} else {
+		suites.Suites = junitSuites(collector)
+	}
+
+	if _, err := io.WriteString(w, goxml.Header); err != nil {
+		return err
+	}
+
+	enc := goxml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitSuites returns the suite of the collected cases,
+// followed by the teardown one, if any.
+func junitSuites(collector *Collector) []JUnitSuite {
+	suite := JUnitSuite{
+		Name:      collector.Name,
+		Timestamp: collector.Start.Format(time.RFC3339),
+		Time:      seconds(time.Since(collector.Start)),
+	}
+
+	teardown := JUnitSuite{
+		Name:      collector.Name + " teardown",
+		Timestamp: suite.Timestamp,
+		Time:      suite.Time,
+	}
+
+	for _, c := range collector.Cases() {
+		if c.Teardown {
+			teardown.Add(c)
+		} else {
+			suite.Add(c)
+		}
+	}
+
+	suites := []JUnitSuite{suite}
+	if teardown.Tests > 0 {
+		suites = append(suites, teardown)
+	}
+
+	return suites
+}
+
+// Add adds a case to the suite.
+func (suite *JUnitSuite) Add(c *Case) {
+	jc := JUnitCase{
+		Name:      c.Name,
+		ClassName: c.ClassName,
+		Time:      seconds(c.Duration),
+		SystemOut: strings.Join(c.Output, "\n\n"),
+	}
+
+	if len(c.Errors) > 0 {
+		jc.Error = &JUnitFailure{
+			Message: c.Errors[0],
+			Text:    strings.Join(c.Errors, "\n"),
+		}
+		suite.Errors++
+	} else if len(c.Failures) > 0 {
+		jc.Failure = &JUnitFailure{
+			Message: c.Failures[0],
+			Text:    strings.Join(c.Failures, "\n"),
+		}
+		suite.Failures++
+	} else if !c.Done {
+		jc.Skipped = &JUnitSkipped{Message: c.SkipReason}
+	}
+
+	suite.Cases = append(suite.Cases, jc)
+	suite.Tests = len(suite.Cases)
+}
+
+// seconds formats a duration as seconds.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,100 @@
+package report_test
+
+import (
+	"bytes"
+	goxml "encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/report"
+)
+
+func Test_JUnit(T *testing.T) {
+	collector := report.NewCollector()
+	collector.Name = "Petstore"
+
+	ok := collector.Begin("getPetById", "GET /pet/{petId}")
+	ok.Output = append(ok.Output, "GET /pet/1")
+	ok.End(true)
+
+	failed := collector.Begin("deleteUser", "DELETE /user/{username}")
+	failed.Fail("Expected the 200 status in response, but got 404.")
+	failed.End(false)
+
+	collector.Begin("never finished", "GET /")
+
+	buf := &bytes.Buffer{}
+	assert.Nil(T, report.JUnit(collector, buf))
+
+	suites := report.JUnitSuites{}
+	assert.Nil(T, goxml.Unmarshal(buf.Bytes(), &suites))
+	assert.Len(T, suites.Suites, 1)
+
+	suite := suites.Suites[0]
+	assert.Equal(T, "Petstore", suite.Name)
+	assert.Equal(T, 3, suite.Tests)
+	assert.Equal(T, 1, suite.Failures)
+
+	assert.Equal(T, "getPetById", suite.Cases[0].Name)
+	assert.Nil(T, suite.Cases[0].Failure)
+	assert.Equal(T, "GET /pet/1", suite.Cases[0].SystemOut)
+
+	assert.Equal(T, "Expected the 200 status in response, but got 404.", suite.Cases[1].Failure.Message)
+	assert.NotNil(T, suite.Cases[2].Skipped)
+}
+
+func Test_JUnit_Teardown(T *testing.T) {
+	collector := report.NewCollector()
+	collector.Name = "Petstore"
+
+	collector.Begin("createPet", "POST /pet").End(true)
+
+	collector.TearingDown()
+	collector.Begin("deletePet", "DELETE /pet/{petId}").End(false)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(T, report.JUnit(collector, buf))
+
+	suites := report.JUnitSuites{}
+	assert.Nil(T, goxml.Unmarshal(buf.Bytes(), &suites))
+	assert.Len(T, suites.Suites, 2)
+
+	assert.Equal(T, "Petstore", suites.Suites[0].Name)
+	assert.Equal(T, 0, suites.Suites[0].Failures)
+	assert.Equal(T, "createPet", suites.Suites[0].Cases[0].Name)
+
+	assert.Equal(T, "Petstore teardown", suites.Suites[1].Name)
+	assert.Equal(T, 1, suites.Suites[1].Failures)
+	assert.Equal(T, "deletePet", suites.Suites[1].Cases[0].Name)
+}
+
+func Test_JUnit_Suite(T *testing.T) {
+	collector := report.NewCollector()
+	collector.Name = "script/noosa"
+
+	credit := collector.Script("script/noosa/credit.yaml")
+	credit.Begin("login.pinLogin", "POST /login").End(true)
+	credit.TearingDown()
+	credit.Begin("cancel", "POST /cancel").End(false)
+
+	test := collector.Script("script/noosa/test.yaml")
+	test.Begin("getCards", "GET /cards").End(true)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(T, report.JUnit(collector, buf))
+
+	suites := report.JUnitSuites{}
+	assert.Nil(T, goxml.Unmarshal(buf.Bytes(), &suites))
+	assert.Len(T, suites.Suites, 3)
+
+	assert.Equal(T, "script/noosa/credit.yaml", suites.Suites[0].Name)
+	assert.Equal(T, 1, suites.Suites[0].Tests)
+	assert.Equal(T, "script/noosa/credit.yaml teardown", suites.Suites[1].Name)
+	assert.Equal(T, 1, suites.Suites[1].Failures)
+	assert.Equal(T, "script/noosa/test.yaml", suites.Suites[2].Name)
+	assert.Equal(T, "getCards", suites.Suites[2].Cases[0].Name)
+}
+
+func Test_Write_UnknownFormat(T *testing.T) {
+	assert.NotNil(T, report.Write("pdf", "report.pdf", report.NewCollector()))
+}
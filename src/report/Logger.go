@@ -0,0 +1,228 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Logger is a contract.Logger which feeds the logged events into a Collector
+// to build reports from, while passing them all to the wrapped logger.
+type Logger struct {
+	contract.Logger
+
+	Collector *Collector
+	Case      *Case
+
+	node string
+}
+
+// NewLogger creates a new Logger instance.
+func NewLogger(log contract.Logger, collector *Collector) *Logger {
+	return &Logger{
+		Logger:    log,
+		Collector: collector,
+	}
+}
+
+// Clone creates a copy of the logger which reports to the same collector.
+func (log *Logger) Clone() contract.Logger {
+	return NewLogger(log.Logger.Clone(), log.Collector)
+}
+
+// fail adds a failure message to the current case, if any.
+func (log *Logger) fail(msg string, args ...interface{}) {
+	if log.Case != nil {
+		log.Case.Fail(fmt.Sprintf(msg, args...))
+	}
+}
+
+// LoadingScript names the report after the script.
+func (log *Logger) LoadingScript(path string) {
+	log.Collector.Name = path
+	log.Logger.LoadingScript(path)
+}
+
+// TestingProject names the report after the project.
+func (log *Logger) TestingProject(p contract.ProjectInfo) {
+	log.Collector.Name = p.Title()
+	log.Logger.TestingProject(p)
+}
+
+// ExecutingNode remembers the script node name to use for the next case.
+func (log *Logger) ExecutingNode(node string) {
+	log.node = node
+	log.Logger.ExecutingNode(node)
+}
+
+// NodeSkipped adds a case which is never finished, so it is reported as skipped.
+func (log *Logger) NodeSkipped(node string, reason string) {
+	c := log.Collector.Begin(node, "")
+	c.SkipReason = reason
+
+	log.Logger.NodeSkipped(node, reason)
+}
+
+// NodeRestored adds a passed case for the node restored from a run state.
+func (log *Logger) NodeRestored(node string) {
+	c := log.Collector.Begin(node, "")
+	c.Output = append(c.Output, "Restored from the run state.")
+	c.End(true)
+
+	log.Logger.NodeRestored(node)
+}
+
+// TearingDown makes the following cases teardown ones.
+func (log *Logger) TearingDown() {
+	log.Collector.TearingDown()
+	log.Logger.TearingDown()
+}
+
+// CleaningUp makes the following cases teardown ones.
+func (log *Logger) CleaningUp() {
+	log.Collector.TearingDown()
+	log.Logger.CleaningUp()
+}
+
+// TestingOperation starts a new case.
+func (log *Logger) TestingOperation(op contract.Operation) {
+	name := log.node
+	if name == "" {
+		name = op.Name()
+	}
+
+	if name == "" {
+		name = op.ID()
+	}
+
+	log.Case = log.Collector.Begin(name, op.Method()+" "+op.Path())
+	log.node = ""
+
+	log.Logger.TestingOperation(op)
+}
+
+// UsingExecutionGraph stores the script execution graph.
+func (log *Logger) UsingExecutionGraph(graph gcontract.Graph) {
+	log.Collector.SetGraph(graph)
+	log.Logger.UsingExecutionGraph(graph)
+}
+
+// UsingParameterExample captures the request parameter.
+func (log *Logger) UsingParameterExample(paramName string, in string, container string, value string) {
+	if log.Case != nil {
+		log.Case.Parameters = append(log.Case.Parameters, Parameter{
+			In:     in,
+			Name:   paramName,
+			Value:  value,
+			Source: container,
+		})
+	}
+
+	log.Logger.UsingParameterExample(paramName, in, container, value)
+}
+
+// ReceivedResponse captures the response.
+func (log *Logger) ReceivedResponse(result *contract.OperationResult) {
+	if log.Case != nil {
+		log.Case.Output = append(log.Case.Output, Dump(result))
+		log.Case.Result = result
+	}
+
+	log.Logger.ReceivedResponse(result)
+}
+
+// Error adds an error to the current case.
+func (log *Logger) Error(err error) {
+	if log.Case != nil {
+		log.Case.Errors = append(log.Case.Errors, err.Error())
+	}
+
+	log.Logger.Error(err)
+}
+
+// HeaderHasNoValue adds a failure to the current case.
+func (log *Logger) HeaderHasNoValue(hdr string) {
+	log.fail("Header \"%s\" is required but is not present.", hdr)
+	log.Logger.HeaderHasNoValue(hdr)
+}
+
+// ResponseHasWrongStatus adds a failure to the current case.
+func (log *Logger) ResponseHasWrongStatus(expectedStatus int, actualStatus int) {
+	log.fail("Expected the %d status in response, but got %d.", expectedStatus, actualStatus)
+	log.Logger.ResponseHasWrongStatus(expectedStatus, actualStatus)
+}
+
+// ResponseHasWrongContentType adds a failure to the current case.
+func (log *Logger) ResponseHasWrongContentType(expectedCT string, actualCT string) {
+	log.fail("Expected the %s Content-Type in response, but got %s.", expectedCT, actualCT)
+	log.Logger.ResponseHasWrongContentType(expectedCT, actualCT)
+}
+
+// ResponseHasWrongPropertyValue adds a failure to the current case.
+func (log *Logger) ResponseHasWrongPropertyValue(propName string, expected string, actual string) {
+	log.fail("Expected the %s property to equal %s but got %s.", propName, expected, actual)
+	log.Logger.ResponseHasWrongPropertyValue(propName, expected, actual)
+}
+
+// ResponseHasWrongContent adds a failure to the current case.
+func (log *Logger) ResponseHasWrongContent(what string, expected string, actual string) {
+	log.fail("Expected the response %s to be %s but got %s.", what, expected, actual)
+	log.Logger.ResponseHasWrongContent(what, expected, actual)
+}
+
+// AssertionFailed adds a failure to the current case.
+func (log *Logger) AssertionFailed(assertion string, actual string) {
+	log.fail("Expected %s, but got %s.", assertion, actual)
+	log.Logger.AssertionFailed(assertion, actual)
+}
+
+// ResponseHasDrift adds a failure to the current case.
+func (log *Logger) ResponseHasDrift(d contract.Drift) {
+	if d.Details != "" {
+		log.fail("The %s %s violates the spec (%s).", d.Kind, d.Subject, d.Details)
+	} else {
+		log.fail("The %s %s is not declared in the spec.", d.Kind, d.Subject)
+	}
+
+	log.Logger.ResponseHasDrift(d)
+}
+
+// SchemaFail adds a failure to the current case.
+func (log *Logger) SchemaFail(schemaName string, errors []gojsonschema.ResultError) {
+	msgs := []string{schemaName + " schema failure."}
+	for _, err := range errors {
+		msgs = append(msgs, err.String())
+
+		if log.Case != nil {
+			log.Case.SchemaErrors = append(log.Case.SchemaErrors, SchemaError{
+				Schema:      schemaName,
+				Field:       err.Field(),
+				Description: err.Description(),
+			})
+		}
+	}
+
+	log.fail("%s", strings.Join(msgs, "\n"))
+	log.Logger.SchemaFail(schemaName, errors)
+}
+
+// OperationOK finishes the current case successfully.
+func (log *Logger) OperationOK() {
+	if log.Case != nil {
+		log.Case.End(true)
+	}
+
+	log.Logger.OperationOK()
+}
+
+// OperationFail finishes the current case unsuccessfully.
+func (log *Logger) OperationFail() {
+	if log.Case != nil {
+		log.Case.End(false)
+	}
+
+	log.Logger.OperationFail()
+}
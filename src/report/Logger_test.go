@@ -0,0 +1,58 @@
+package report_test
+
+import (
+	goerrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/report"
+	"github.com/x1n13y84issmd42/oasis/src/utility"
+)
+
+func Test_Logger(T *testing.T) {
+	collector := report.NewCollector()
+	logger := report.NewLogger(log.NewPlain(0), collector)
+	spec := utility.Load("../../spec/test/oas3.yaml", logger)
+
+	T.Run("OK", func(T *testing.T) {
+		op := spec.GetOperation("getPetById")
+		opLog := op.GetLogger()
+
+		opLog.ExecutingNode("get pet")
+		opLog.TestingOperation(op)
+		opLog.ReceivedResponse(&contract.OperationResult{
+			HTTPResponse: &http.Response{
+				Proto:  "HTTP/1.1",
+				Status: "200 OK",
+				Header: http.Header{"Content-Type": []string{"application/json"}},
+			},
+			ResponseBytes: []byte(`{"id":1}`),
+		})
+		opLog.OperationOK()
+
+		c := collector.Cases()[0]
+		assert.Equal(T, "get pet", c.Name)
+		assert.Equal(T, "GET /pet/{petId}", c.ClassName)
+		assert.True(T, c.Success)
+		assert.Equal(T, []string{"HTTP/1.1 200 OK\nContent-Type: application/json\n\n{\"id\":1}"}, c.Output)
+	})
+
+	T.Run("Failure", func(T *testing.T) {
+		op := spec.GetOperation("deleteUser")
+		opLog := op.GetLogger()
+
+		opLog.TestingOperation(op)
+		opLog.ResponseHasWrongStatus(200, 404)
+		opLog.Error(goerrors.New("oops"))
+		opLog.OperationFail()
+
+		c := collector.Cases()[1]
+		assert.Equal(T, "Delete user", c.Name)
+		assert.False(T, c.Success)
+		assert.Equal(T, []string{"Expected the 200 status in response, but got 404."}, c.Failures)
+		assert.Equal(T, []string{"oops"}, c.Errors)
+	})
+}
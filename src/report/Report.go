@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+// Writer is a function to write a report in some format.
+type Writer func(collector *Collector, w io.Writer) error
+
+// Formats are the available report formats.
+var Formats = map[string]Writer{
+	"junit": JUnit,
+	"html":  HTML,
+}
+
+// Write writes a report in the given format to the file at path.
+// Secret values are masked in the report the same way as in logs.
+func Write(format string, path string, collector *Collector) error {
+	writer, ok := Formats[format]
+	if !ok {
+		return errors.NotFound("Report format", format, nil)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writer(collector, buf); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Oops("Failed to create the report file '"+path+"'.", err)
+	}
+	defer file.Close()
+
+	_, err = io.WriteString(file, log.Mask(buf.String()))
+	return err
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"strconv"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// BinaryResponse tests opaque binary response bodies, such as images.
+// Only the body size can be tested against the schema: the minLength
+// & maxLength values of a string schema (format: binary) limit the number of bytes.
+func BinaryResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	size := result.Size()
+	success := true
+
+	if min, ok := SchemaLimit(schema, "minLength"); ok && size < min {
+		log.ResponseHasWrongContent("size", ">= "+strconv.FormatInt(min, 10), strconv.FormatInt(size, 10))
+		success = false
+	}
+
+	if max, ok := SchemaLimit(schema, "maxLength"); ok && size > max {
+		log.ResponseHasWrongContent("size", "<= "+strconv.FormatInt(max, 10), strconv.FormatInt(size, 10))
+		success = false
+	}
+
+	if success {
+		log.SchemaOK(schema.Name)
+	}
+
+	return success
+}
+
+// SchemaLimit reads a numeric schema keyword value.
+func SchemaLimit(schema *api.Schema, keyword string) (int64, bool) {
+	switch v := schema.JSONSchema[keyword].(type) {
+	case float64:
+		return int64(v), true
+
+	case int:
+		return int64(v), true
+
+	case int64:
+		return v, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func TestBinaryResponse(T *testing.T) {
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type":      "string",
+			"format":    "binary",
+			"minLength": float64(2),
+			"maxLength": float64(4),
+		},
+	}
+
+	T.Run("OK", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte{0x89, 0x50, 0x4e},
+		}
+
+		assert.True(T, BinaryResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Too small", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte{0x89},
+		}
+
+		assert.False(T, BinaryResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Too big", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte{0x89, 0x50, 0x4e, 0x47, 0x0d},
+		}
+
+		assert.False(T, BinaryResponse(result, schema, log.NewFestive(0)))
+	})
+}
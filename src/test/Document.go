@@ -0,0 +1,71 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/xml"
+)
+
+// Document returns the response body decoded into generic values
+// (maps, slices, strings, numbers & booleans). The document is decoded
+// once and cached in the result, see contract.OperationResult.Document().
+func Document(result *contract.OperationResult) (interface{}, error) {
+	return result.Document(DecodeDocument)
+}
+
+// DecodeDocument decodes a response body according to it's Content-Type.
+// XML bodies are decoded without a schema, anything else is treated as JSON.
+// JSON is decoded straight from the body reader, so spilled bodies
+// are not read into memory as a whole before decoding.
+func DecodeDocument(result *contract.OperationResult) (interface{}, error) {
+	if result.Size() == 0 {
+		return nil, errors.Oops("The response body is empty.", nil)
+	}
+
+	body, err := result.Body()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if result.HTTPResponse != nil {
+		CT := api.ParseMediaType(result.HTTPResponse.Header.Get("Content-Type"))
+		if CT.IsXML() {
+			data, err := ioutil.ReadAll(body)
+			if err != nil {
+				return nil, err
+			}
+
+			return xml.Decode(data, nil)
+		}
+	}
+
+	var doc interface{}
+
+	decoder := json.NewDecoder(body)
+	err = decoder.Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anything but whitespace after the document makes it invalid.
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, errors.Oops("The response body contains data after the JSON document.", err)
+	}
+
+	return doc, nil
+}
+
+// ReadBody reads the whole response body, wherever it is stored.
+func ReadBody(result *contract.OperationResult) ([]byte, error) {
+	if result.ResponseFile == "" {
+		return result.ResponseBytes, nil
+	}
+
+	return ioutil.ReadFile(result.ResponseFile)
+}
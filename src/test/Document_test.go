@@ -0,0 +1,86 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+func TestDocument(T *testing.T) {
+	T.Run("JSON", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte(`{"id": 42, "tags": ["a", "b"]}`),
+		}
+
+		doc, err := Document(result)
+		assert.Nil(T, err)
+		assert.Equal(T, map[string]interface{}{
+			"id":   float64(42),
+			"tags": []interface{}{"a", "b"},
+		}, doc)
+	})
+
+	T.Run("XML", func(T *testing.T) {
+		result := &contract.OperationResult{
+			HTTPResponse: &http.Response{
+				Header: http.Header{"Content-Type": []string{"application/xml"}},
+			},
+			ResponseBytes: []byte(`<pet><id>42</id></pet>`),
+		}
+
+		doc, err := Document(result)
+		assert.Nil(T, err)
+		assert.Equal(T, "42", doc.(map[string]interface{})["id"])
+	})
+
+	T.Run("Trailing data", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte(`{"id": 42} {"id": 43}`),
+		}
+
+		_, err := Document(result)
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Empty", func(T *testing.T) {
+		_, err := Document(&contract.OperationResult{})
+		assert.NotNil(T, err)
+	})
+}
+
+func TestReadResponseBody(T *testing.T) {
+	limit := BodyMemoryLimit
+	BodyMemoryLimit = 8
+	defer func() {
+		BodyMemoryLimit = limit
+		Cleanup()
+	}()
+
+	T.Run("Memory", func(T *testing.T) {
+		result := &contract.OperationResult{}
+
+		assert.Nil(T, ReadResponseBody(strings.NewReader("[1,2,3]"), result))
+		assert.Equal(T, "[1,2,3]", string(result.ResponseBytes))
+		assert.Equal(T, "", result.ResponseFile)
+	})
+
+	T.Run("Spill", func(T *testing.T) {
+		result := &contract.OperationResult{}
+
+		assert.Nil(T, ReadResponseBody(strings.NewReader("[1,2,3,4,5,6]"), result))
+		assert.Nil(T, result.ResponseBytes)
+		assert.NotEqual(T, "", result.ResponseFile)
+		assert.Equal(T, int64(13), result.Size())
+
+		data, err := ReadBody(result)
+		assert.Nil(T, err)
+		assert.Equal(T, "[1,2,3,4,5,6]", string(data))
+
+		doc, err := Document(result)
+		assert.Nil(T, err)
+		assert.Len(T, doc, 6)
+	})
+}
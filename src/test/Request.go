@@ -1,7 +1,6 @@
 package test
 
 import (
-	"io/ioutil"
 	"net/http"
 
 	"github.com/x1n13y84issmd42/oasis/src/contract"
@@ -57,14 +56,16 @@ func (req *Request) Execute() *contract.OperationResult {
 	}
 
 	req.Result.HTTPResponse = response
-	//TODO: this may fail on very large responses (GB++)
-	//TODO: handle the error.
-	req.Result.ResponseBytes, _ = ioutil.ReadAll(response.Body)
+	defer response.Body.Close()
+
+	err = ReadResponseBody(response.Body, req.Result)
 
 	if err != nil {
 		req.Log.Error(err)
 		req.Result.Success = false
 	}
 
+	req.Log.ReceivedResponse(req.Result)
+
 	return req.Result
 }
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// BodyMemoryLimit is the maximum response body size in bytes kept in memory.
+// Larger bodies are spilled into temporary files.
+var BodyMemoryLimit int64 = 32 * 1024 * 1024
+
+var spillDir string
+var spillDirOnce sync.Once
+var spillDirErr error
+
+// SpillDir returns a temporary directory to store large response bodies in.
+// It is created on first use and removed by Cleanup().
+func SpillDir() (string, error) {
+	spillDirOnce.Do(func() {
+		spillDir, spillDirErr = ioutil.TempDir("", "oasis-")
+	})
+
+	return spillDir, spillDirErr
+}
+
+// Cleanup removes the temporary files created for large response bodies.
+func Cleanup() {
+	if spillDir != "" {
+		os.RemoveAll(spillDir)
+	}
+}
+
+// ReadResponseBody reads a response body into the result.
+// Bodies up to BodyMemoryLimit bytes are kept in ResponseBytes,
+// larger ones are streamed into a temporary file (see ResponseFile).
+func ReadResponseBody(body io.Reader, result *contract.OperationResult) error {
+	buf := &bytes.Buffer{}
+	n, err := io.CopyN(buf, body, BodyMemoryLimit+1)
+
+	if err == io.EOF || (err == nil && n <= BodyMemoryLimit) {
+		result.ResponseBytes = buf.Bytes()
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	dir, err := SpillDir()
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(dir, "body-")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, io.MultiReader(buf, body))
+	if err != nil {
+		return err
+	}
+
+	result.ResponseFile = file.Name()
+	result.ResponseSize = size
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// StreamLineLimit is the maximum length of a single line
+// in newline-delimited & event stream bodies.
+var StreamLineLimit = 16 * 1024 * 1024
+
+// NDJSONResponse tests newline-delimited JSON response bodies.
+// Every non-empty line is a separate JSON document, and is validated
+// against the item schema (see api.Schema.Items()).
+func NDJSONResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	item := schema.Items()
+	success := true
+
+	body, err := result.Body()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), StreamLineLimit)
+
+	for i := 0; scanner.Scan(); i++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var data interface{}
+		if err := json.Unmarshal(line, &data); err != nil {
+			log.Error(err)
+			success = false
+			continue
+		}
+
+		success = StreamItem(data, item, i, log) && success
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Error(err)
+		return false
+	}
+
+	return success
+}
+
+// SSEEvent is a single Server-Sent Event.
+type SSEEvent struct {
+	Event string
+	ID    string
+	Data  string
+}
+
+// ParseSSE parses a text/event-stream body into a list of events.
+func ParseSSE(body io.Reader) ([]SSEEvent, error) {
+	events := []SSEEvent{}
+	ev := SSEEvent{}
+	data := []string{}
+
+	dispatch := func() {
+		if len(data) > 0 {
+			ev.Data = strings.Join(data, "\n")
+			events = append(events, ev)
+		}
+
+		ev = SSEEvent{}
+		data = []string{}
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), StreamLineLimit)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if line == "" {
+			dispatch()
+			continue
+		}
+
+		// Comments.
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i != -1 {
+			field = line[:i]
+			value = strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			ev.Event = value
+		case "id":
+			ev.ID = value
+		}
+	}
+
+	dispatch()
+
+	return events, scanner.Err()
+}
+
+// EventStreamResponse tests Server-Sent Events response bodies.
+// The data of every event is validated against the item schema
+// (see api.Schema.Items()). JSON data is unmarshaled first,
+// anything else is validated as a string.
+func EventStreamResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	item := schema.Items()
+	success := true
+
+	body, err := result.Body()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer body.Close()
+
+	events, err := ParseSSE(body)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	for i, ev := range events {
+		var data interface{}
+		if err := json.Unmarshal([]byte(ev.Data), &data); err != nil {
+			data = ev.Data
+		}
+
+		success = StreamItem(data, item, i, log) && success
+	}
+
+	return success
+}
+
+// StreamItem validates a single item of a streamed response.
+func StreamItem(data interface{}, item *api.Schema, i int, log contract.Logger) bool {
+	return Schema(data, &api.Schema{
+		Name:       fmt.Sprintf("%s #%d", item.Name, i),
+		JSONSchema: item.JSONSchema,
+	}, log)
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func streamSchema() *api.Schema {
+	return &api.Schema{
+		Name: "Exports",
+		JSONSchema: api.JSONSchema{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"id"},
+			},
+		},
+	}
+}
+
+func TestNDJSONResponse(T *testing.T) {
+	T.Run("OK", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("{\"id\":1}\n\n{\"id\":2}\n"),
+		}
+
+		assert.True(T, NDJSONResponse(result, streamSchema(), log.NewFestive(0)))
+	})
+
+	T.Run("Schema", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("{\"id\":1}\n{\"name\":2}\n"),
+		}
+
+		assert.False(T, NDJSONResponse(result, streamSchema(), log.NewFestive(0)))
+	})
+
+	T.Run("Malformed", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("{\"id\":1}\n{\"id\":\n"),
+		}
+
+		assert.False(T, NDJSONResponse(result, streamSchema(), log.NewFestive(0)))
+	})
+}
+
+func TestParseSSE(T *testing.T) {
+	body := strings.NewReader(": keep-alive\n\nevent: update\nid: 1\ndata: {\"id\":\ndata: 1}\n\ndata: bye\r\n")
+
+	expected := []SSEEvent{
+		{Event: "update", ID: "1", Data: "{\"id\":\n1}"},
+		{Data: "bye"},
+	}
+
+	events, err := ParseSSE(body)
+	assert.Nil(T, err)
+	assert.Equal(T, expected, events)
+}
+
+func TestEventStreamResponse(T *testing.T) {
+	T.Run("OK", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("data: {\"id\":1}\n\ndata: {\"id\":2}\n\n"),
+		}
+
+		assert.True(T, EventStreamResponse(result, streamSchema(), log.NewFestive(0)))
+	})
+
+	T.Run("Schema", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("data: {\"id\":1}\n\ndata: not json\n\n"),
+		}
+
+		assert.False(T, EventStreamResponse(result, streamSchema(), log.NewFestive(0)))
+	})
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+)
+
+// PropertyDrift is a response property which is not declared in the schema,
+// or is declared as writeOnly and thus must not be present in responses.
+type PropertyDrift struct {
+	Path    string
+	Details string
+}
+
+// UndeclaredProperties walks the data along the schema and collects
+// properties not declared in it. In strict mode additionalProperties
+// is treated as false, unless the schema declares it explicitly.
+// Object schemas without any properties are free-form and are not checked.
+func UndeclaredProperties(data interface{}, schema *api.Schema) []PropertyDrift {
+	walker := &PropertyWalker{
+		Root: map[string]interface{}(schema.JSONSchema),
+	}
+
+	walker.Walk(data, walker.Root, "")
+
+	return walker.Drifts
+}
+
+// ReadOnlyProperties returns a sorted list of the top-level schema properties
+// declared as readOnly, those which must not be sent in requests.
+func ReadOnlyProperties(schema *api.Schema) []string {
+	walker := &PropertyWalker{
+		Root: map[string]interface{}(schema.JSONSchema),
+	}
+
+	res := []string{}
+	props, _, _ := walker.Collect(walker.Root)
+
+	for name, prop := range props {
+		if walker.Flag(prop, "readOnly") {
+			res = append(res, name)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// PropertyWalker walks data along it's JSON schema.
+type PropertyWalker struct {
+	Root   map[string]interface{}
+	Drifts []PropertyDrift
+}
+
+// Walk walks the data recursively.
+func (walker *PropertyWalker) Walk(data interface{}, schema map[string]interface{}, path string) {
+	schema = walker.Resolve(schema)
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		props, additional, free := walker.Collect(schema)
+
+		keys := []string{}
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			keyPath := strings.TrimPrefix(path+"."+key, ".")
+
+			if prop, ok := props[key]; ok {
+				if walker.Flag(prop, "writeOnly") {
+					walker.Drifts = append(walker.Drifts, PropertyDrift{keyPath, "writeOnly"})
+				}
+
+				walker.Walk(v[key], prop, keyPath)
+			} else if additional != nil {
+				walker.Walk(v[key], additional, keyPath)
+			} else if !free {
+				walker.Drifts = append(walker.Drifts, PropertyDrift{keyPath, ""})
+			}
+		}
+
+	case []interface{}:
+		items := walker.Schema(schema["items"])
+		if items == nil {
+			return
+		}
+
+		for i, item := range v {
+			walker.Walk(item, items, path+"["+strconv.Itoa(i)+"]")
+		}
+	}
+}
+
+// Collect collects object properties from the schema and it's
+// allOf, anyOf & oneOf subschemas. It also returns the additionalProperties
+// schema, if any, and a flag telling whether the object is free-form,
+// i.e. declares no properties or allows any additional ones.
+func (walker *PropertyWalker) Collect(schema map[string]interface{}) (map[string]map[string]interface{}, map[string]interface{}, bool) {
+	props := map[string]map[string]interface{}{}
+	var additional map[string]interface{}
+	free := false
+
+	var collect func(s map[string]interface{}, depth int)
+	collect = func(s map[string]interface{}, depth int) {
+		s = walker.Resolve(s)
+		if s == nil || depth > 32 {
+			return
+		}
+
+		if sprops, ok := s["properties"].(map[string]interface{}); ok {
+			for name, prop := range sprops {
+				if sprop := walker.Schema(prop); sprop != nil {
+					props[name] = sprop
+				}
+			}
+		}
+
+		switch ap := s["additionalProperties"].(type) {
+		case bool:
+			free = free || ap
+
+		case map[string]interface{}:
+			additional = ap
+		}
+
+		for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+			if subs, ok := s[k].([]interface{}); ok {
+				for _, sub := range subs {
+					collect(walker.Schema(sub), depth+1)
+				}
+			}
+		}
+	}
+
+	collect(schema, 0)
+
+	return props, additional, free || len(props) == 0
+}
+
+// Resolve follows local $refs like "#/components/schemas/Pet".
+func (walker *PropertyWalker) Resolve(schema map[string]interface{}) map[string]interface{} {
+	for i := 0; schema != nil && i < 32; i++ {
+		ref, ok := schema["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return schema
+		}
+
+		var node interface{} = walker.Root
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			m := walker.Schema(node)
+			if m == nil {
+				return nil
+			}
+
+			node = m[key]
+		}
+
+		schema = walker.Schema(node)
+	}
+
+	return schema
+}
+
+// Schema casts a value to a schema map.
+func (walker *PropertyWalker) Schema(v interface{}) map[string]interface{} {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		return s
+
+	case api.JSONSchema:
+		return map[string]interface{}(s)
+	}
+
+	return nil
+}
+
+// Flag tells whether a boolean schema keyword is set.
+func (walker *PropertyWalker) Flag(schema map[string]interface{}, keyword string) bool {
+	v, ok := walker.Resolve(schema)[keyword].(bool)
+	return ok && v
+}
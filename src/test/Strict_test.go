@@ -0,0 +1,95 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+)
+
+func strictSchema() *api.Schema {
+	return &api.Schema{
+		Name: "Pet",
+		JSONSchema: api.JSONSchema{
+			"$ref": "#/components/schemas/Pet",
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"Pet": map[string]interface{}{
+						"type": "object",
+						"allOf": []interface{}{
+							map[string]interface{}{
+								"properties": map[string]interface{}{
+									"id": map[string]interface{}{"type": "integer", "readOnly": true},
+								},
+							},
+						},
+						"properties": map[string]interface{}{
+							"name":     map[string]interface{}{"type": "string"},
+							"password": map[string]interface{}{"type": "string", "writeOnly": true},
+							"tags": map[string]interface{}{
+								"type": "array",
+								"items": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"name": map[string]interface{}{"type": "string"},
+									},
+								},
+							},
+							"meta": map[string]interface{}{
+								"type":                 "object",
+								"additionalProperties": true,
+								"properties":           map[string]interface{}{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUndeclaredProperties(T *testing.T) {
+	T.Run("OK", func(T *testing.T) {
+		data := map[string]interface{}{
+			"id":   float64(1),
+			"name": "Rex",
+			"tags": []interface{}{
+				map[string]interface{}{"name": "good"},
+			},
+			"meta": map[string]interface{}{"anything": "goes"},
+		}
+
+		assert.Empty(T, UndeclaredProperties(data, strictSchema()))
+	})
+
+	T.Run("Drift", func(T *testing.T) {
+		data := map[string]interface{}{
+			"name":     "Rex",
+			"password": "secret",
+			"color":    "brown",
+			"tags": []interface{}{
+				map[string]interface{}{"name": "good", "weight": float64(1)},
+			},
+		}
+
+		expected := []PropertyDrift{
+			{"color", ""},
+			{"password", "writeOnly"},
+			{"tags[0].weight", ""},
+		}
+
+		assert.Equal(T, expected, UndeclaredProperties(data, strictSchema()))
+	})
+
+	T.Run("Free-form", func(T *testing.T) {
+		schema := &api.Schema{
+			JSONSchema: api.JSONSchema{"type": "object"},
+		}
+
+		assert.Empty(T, UndeclaredProperties(map[string]interface{}{"a": "b"}, schema))
+	})
+}
+
+func TestReadOnlyProperties(T *testing.T) {
+	assert.Equal(T, []string{"id"}, ReadOnlyProperties(strictSchema()))
+}
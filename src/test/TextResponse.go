@@ -0,0 +1,81 @@
+package test
+
+import (
+	"encoding/csv"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+)
+
+// TextResponse tests textual response bodies. The body is treated
+// as a single string value, so string schemas with pattern
+// & length limits apply to it.
+func TextResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	data, err := ReadBody(result)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	return Schema(string(data), schema, log)
+}
+
+// CSVResponse tests CSV response bodies. When the schema is an array,
+// the body is parsed into a list of rows, each one being an object
+// keyed by the header row values. Otherwise it is tested as text.
+func CSVResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	if schema.JSONSchema["type"] != "array" {
+		return TextResponse(result, schema, log)
+	}
+
+	body, err := result.Body()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer body.Close()
+
+	records, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	rows := []interface{}{}
+	item := schema.Items()
+
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+
+		row := map[string]interface{}{}
+		for ci, v := range record {
+			if ci < len(records[0]) {
+				row[records[0][ci]] = CSVCast(v, records[0][ci], item)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return Schema(rows, schema, log)
+}
+
+// CSVCast casts a CSV value to a native type according to the item schema
+// property with the same name, when there is one.
+func CSVCast(v string, column string, item *api.Schema) interface{} {
+	props, ok := item.JSONSchema["properties"].(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	prop, ok := props[column].(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	return (&api.Schema{
+		JSONSchema: prop,
+	}).Cast(v)
+}
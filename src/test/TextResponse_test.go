@@ -0,0 +1,85 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func TestTextResponse(T *testing.T) {
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type":      "string",
+			"pattern":   "^OK [0-9]+$",
+			"maxLength": 10,
+		},
+	}
+
+	T.Run("OK", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("OK 42"),
+		}
+
+		assert.True(T, TextResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Pattern", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("NOT OK"),
+		}
+
+		assert.False(T, TextResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Length", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("OK 4242424242"),
+		}
+
+		assert.False(T, TextResponse(result, schema, log.NewFestive(0)))
+	})
+}
+
+func TestCSVResponse(T *testing.T) {
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"id", "name"},
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type": "integer",
+					},
+				},
+			},
+		},
+	}
+
+	T.Run("OK", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("id,name\n1,doggie\n2,kitty\n"),
+		}
+
+		assert.True(T, CSVResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Types", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("id,name\none,doggie\n"),
+		}
+
+		assert.False(T, CSVResponse(result, schema, log.NewFestive(0)))
+	})
+
+	T.Run("Malformed", func(T *testing.T) {
+		result := &contract.OperationResult{
+			ResponseBytes: []byte("id,name\n1,\"doggie\n"),
+		}
+
+		assert.False(T, CSVResponse(result, schema, log.NewFestive(0)))
+	})
+}
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/xml"
+)
+
+// XMLResponse tests XML response bodies.
+// The body is decoded according to the schema's XML objects
+// and then validated against the very same schema as JSON would be.
+func XMLResponse(result *contract.OperationResult, schema *api.Schema, log contract.Logger) bool {
+	if result.Size() == 0 {
+		return false
+	}
+
+	body, err := ReadBody(result)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	data, err := xml.Decode(body, schema.JSONSchema)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+
+	return Schema(data, schema, log)
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func xmlPetSchema() *api.Schema {
+	return &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"$ref": "#/components/schemas/Pet",
+			"components": map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"Pet": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"name", "photoUrls"},
+						"properties": map[string]interface{}{
+							"id": map[string]interface{}{
+								"type": "integer",
+								"xml": map[string]interface{}{
+									"attribute": true,
+								},
+							},
+							"name": map[string]interface{}{
+								"type": "string",
+							},
+							"photoUrls": map[string]interface{}{
+								"type": "array",
+								"xml": map[string]interface{}{
+									"name":    "photoUrls",
+									"wrapped": true,
+								},
+								"items": map[string]interface{}{
+									"type": "string",
+								},
+							},
+						},
+						"xml": map[string]interface{}{
+							"name": "Pet",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestXMLResponse(T *testing.T) {
+	result := &contract.OperationResult{
+		ResponseBytes: []byte(`<?xml version="1.0"?>
+			<Pet id="42">
+				<name>doggie</name>
+				<photoUrls><photoUrl>a.png</photoUrl><photoUrl>b.png</photoUrl></photoUrls>
+			</Pet>`),
+	}
+
+	assert.True(T, XMLResponse(result, xmlPetSchema(), log.NewFestive(0)))
+}
+
+func TestXMLResponse_False_Schema(T *testing.T) {
+	result := &contract.OperationResult{
+		ResponseBytes: []byte(`<Pet id="forty two"><name>doggie</name></Pet>`),
+	}
+
+	assert.False(T, XMLResponse(result, xmlPetSchema(), log.NewFestive(0)))
+}
+
+func TestXMLResponse_False_Malformed(T *testing.T) {
+	result := &contract.OperationResult{
+		ResponseBytes: []byte(`<Pet><name>doggie</Pet>`),
+	}
+
+	assert.False(T, XMLResponse(result, xmlPetSchema(), log.NewFestive(0)))
+}
@@ -1,13 +1,18 @@
 package expect
 
 import (
-	"encoding/json"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
 	"strconv"
 	"strings"
 
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/test"
 )
@@ -63,9 +68,9 @@ func ContentType(v string, log contract.Logger) contract.Expectation {
 			return false
 		}
 
-		// This is to get rid of the possible "; charset=utf-8" part.
-		respCT := strings.Split(result.HTTPResponse.Header.Get("Content-Type"), ";")[0]
-		if respCT == v {
+		// The expected value may be a pattern like "application/*"
+		// which comes from a spec content map.
+		if api.MatchMediaType(v, result.HTTPResponse.Header.Get("Content-Type")) {
 			return true
 		}
 
@@ -76,6 +81,7 @@ func ContentType(v string, log contract.Logger) contract.Expectation {
 
 // ContentSchema creates an expectation as for response's body
 // structure which must comply to the provided JSON schema.
+// XML bodies are decoded according to the schema's XML objects first.
 func ContentSchema(schema *api.Schema, log contract.Logger) contract.Expectation {
 	log.Expecting("content schema", schema.Name)
 
@@ -84,16 +90,46 @@ func ContentSchema(schema *api.Schema, log contract.Logger) contract.Expectation
 			return false
 		}
 
-		respCT := strings.Split(result.HTTPResponse.Header.Get("Content-Type"), ";")[0]
+		respCT := api.ParseMediaType(result.HTTPResponse.Header.Get("Content-Type"))
 
-		switch respCT {
-		case "application/json":
+		switch {
+		case respCT.IsJSON():
 			if test.JSONResponse(result, schema, log) {
 				return true
 			}
 
+		case respCT.IsXML():
+			if test.XMLResponse(result, schema, log) {
+				return true
+			}
+
+		case respCT.IsNDJSON():
+			if test.NDJSONResponse(result, schema, log) {
+				return true
+			}
+
+		case respCT.IsEventStream():
+			if test.EventStreamResponse(result, schema, log) {
+				return true
+			}
+
+		case respCT.IsCSV():
+			if test.CSVResponse(result, schema, log) {
+				return true
+			}
+
+		case respCT.IsText():
+			if test.TextResponse(result, schema, log) {
+				return true
+			}
+
+		case respCT.IsBinary():
+			if test.BinaryResponse(result, schema, log) {
+				return true
+			}
+
 		default:
-			log.NOMESSAGE("The Content-Type of '%s' is not supported.\n", respCT)
+			log.NOMESSAGE("The Content-Type of '%s' is not supported.\n", respCT.Essence())
 		}
 
 		return false
@@ -113,18 +149,28 @@ func JSONBody(props contract.Set, graph gcontract.Graph, log contract.Logger) co
 			return false
 		}
 
-		respCT := strings.Split(result.HTTPResponse.Header.Get("Content-Type"), ";")[0]
+		// Nothing to expect, so any content type works.
+		if numProps == 0 {
+			return true
+		}
+
+		respCT := api.ParseMediaType(result.HTTPResponse.Header.Get("Content-Type"))
 
-		switch respCT {
-		case "application/json":
+		switch {
+		case respCT.IsJSON():
 			if numProps > 0 {
-				data := make(map[string]interface{})
-				err := json.Unmarshal(result.ResponseBytes, &data)
+				doc, err := test.Document(result)
 				if err != nil {
 					log.Error(err)
 					return false
 				}
 
+				data, ok := doc.(map[string]interface{})
+				if !ok {
+					log.Error(errors.Oops("The response body is not a JSON object.", nil))
+					return false
+				}
+
 				result := true
 
 				for ebp := range props.Iterate() {
@@ -147,9 +193,80 @@ func JSONBody(props contract.Set, graph gcontract.Graph, log contract.Logger) co
 			return true
 
 		default:
-			log.NOMESSAGE("The Content-Type of '%s' is not supported.\n", respCT)
+			log.NOMESSAGE("The Content-Type of '%s' is not supported.\n", respCT.Essence())
+		}
+
+		return false
+	}
+}
+
+// ContentSize creates an expectation as for response's body size in bytes.
+// Zero limits are not checked.
+func ContentSize(min int64, max int64, log contract.Logger) contract.Expectation {
+	if min > 0 {
+		log.Expecting("content size at least", strconv.FormatInt(min, 10))
+	}
+
+	if max > 0 {
+		log.Expecting("content size at most", strconv.FormatInt(max, 10))
+	}
+
+	return func(result *contract.OperationResult) bool {
+		size := result.Size()
+
+		if min > 0 && size < min {
+			log.ResponseHasWrongContent("size", ">= "+strconv.FormatInt(min, 10), strconv.FormatInt(size, 10))
+			return false
+		}
+
+		if max > 0 && size > max {
+			log.ResponseHasWrongContent("size", "<= "+strconv.FormatInt(max, 10), strconv.FormatInt(size, 10))
+			return false
+		}
+
+		return true
+	}
+}
+
+// ContentChecksum creates an expectation as for response's body checksum.
+// Supported algorithms are "sha256" & "md5", checksums are hex-encoded.
+func ContentChecksum(algo string, checksum string, log contract.Logger) contract.Expectation {
+	log.Expecting("content "+algo, checksum)
+
+	return func(result *contract.OperationResult) bool {
+		var h hash.Hash
+
+		switch algo {
+		case "sha256":
+			h = sha256.New()
+
+		case "md5":
+			h = md5.New()
+
+		default:
+			log.Error(errors.Oops("Unknown checksum algorithm '"+algo+"'.", nil))
+			return false
+		}
+
+		body, err := result.Body()
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		defer body.Close()
+
+		if _, err = io.Copy(h, body); err != nil {
+			log.Error(err)
+			return false
+		}
+
+		actual := hex.EncodeToString(h.Sum(nil))
+
+		if strings.EqualFold(actual, checksum) {
+			return true
 		}
 
+		log.ResponseHasWrongContent(algo, checksum, actual)
 		return false
 	}
 }
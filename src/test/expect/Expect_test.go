@@ -68,6 +68,26 @@ func Test_ContentType(T *testing.T) {
 	T.Run("False", func(T *testing.T) {
 		assert.False(T, expect.ContentType("text/html", log)(result))
 	})
+
+	T.Run("Wildcard", func(T *testing.T) {
+		assert.True(T, expect.ContentType("application/*", log)(result))
+	})
+
+	T.Run("Suffix", func(T *testing.T) {
+		result := &contract.OperationResult{
+			HTTPResponse: &http.Response{
+				Header: http.Header{
+					"Content-Type": []string{
+						"application/problem+json",
+					},
+				},
+			},
+		}
+
+		assert.True(T, expect.ContentType("application/problem+json", log)(result))
+		assert.True(T, expect.ContentType("application/*+json", log)(result))
+		assert.False(T, expect.ContentType("application/json", log)(result))
+	})
 }
 
 func Test_HeaderSchema(T *testing.T) {
@@ -140,3 +160,99 @@ func Test_ContentSchema(T *testing.T) {
 		assert.False(T, expect.ContentSchema(schema, log)(result))
 	})
 }
+
+func Test_ContentSchema_Suffix(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type": []string{
+					"application/vnd.api+json; charset=utf-8",
+				},
+			},
+		},
+		ResponseBytes: []byte("{\"data\":[]}"),
+	}
+
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type": "object",
+			"required": []string{
+				"data",
+			},
+		},
+	}
+
+	assert.True(T, expect.ContentSchema(schema, log)(result))
+}
+
+func Test_ContentSchema_Text(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		HTTPResponse: &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Content-Type": []string{
+					"text/plain; charset=utf-8",
+				},
+			},
+		},
+		ResponseBytes: []byte("pong"),
+	}
+
+	T.Run("True", func(T *testing.T) {
+		schema := &api.Schema{
+			JSONSchema: api.JSONSchema{
+				"type":    "string",
+				"pattern": "^pong$",
+			},
+		}
+
+		assert.True(T, expect.ContentSchema(schema, log)(result))
+	})
+
+	T.Run("False", func(T *testing.T) {
+		schema := &api.Schema{
+			JSONSchema: api.JSONSchema{
+				"type":    "string",
+				"pattern": "^ping$",
+			},
+		}
+
+		assert.False(T, expect.ContentSchema(schema, log)(result))
+	})
+}
+
+func Test_ContentSize(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		ResponseBytes: []byte("1234"),
+	}
+
+	assert.True(T, expect.ContentSize(4, 4, log)(result))
+	assert.True(T, expect.ContentSize(0, 0, log)(result))
+	assert.False(T, expect.ContentSize(5, 0, log)(result))
+	assert.False(T, expect.ContentSize(0, 3, log)(result))
+}
+
+func Test_ContentChecksum(T *testing.T) {
+	log := log.New("plain", 0)
+	result := &contract.OperationResult{
+		ResponseBytes: []byte("hello"),
+	}
+
+	T.Run("sha256", func(T *testing.T) {
+		assert.True(T, expect.ContentChecksum("sha256", "2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824", log)(result))
+		assert.False(T, expect.ContentChecksum("sha256", "0000", log)(result))
+	})
+
+	T.Run("md5", func(T *testing.T) {
+		assert.True(T, expect.ContentChecksum("md5", "5d41402abc4b2a76b9719d911017c592", log)(result))
+		assert.False(T, expect.ContentChecksum("md5", "0000", log)(result))
+	})
+
+	T.Run("Unknown", func(T *testing.T) {
+		assert.False(T, expect.ContentChecksum("crc32", "0000", log)(result))
+	})
+}
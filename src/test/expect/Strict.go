@@ -0,0 +1,182 @@
+package expect
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// StandardHeaders are the response headers which are not expected
+// to be declared in specs, so they are never reported as drifts.
+var StandardHeaders = []string{
+	"Accept-Ranges",
+	"Access-Control-Allow-Credentials",
+	"Access-Control-Allow-Headers",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Origin",
+	"Access-Control-Expose-Headers",
+	"Access-Control-Max-Age",
+	"Age",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Length",
+	"Content-Type",
+	"Date",
+	"Etag",
+	"Expires",
+	"Keep-Alive",
+	"Last-Modified",
+	"Server",
+	"Strict-Transport-Security",
+	"Transfer-Encoding",
+	"Vary",
+	"Via",
+}
+
+// DeclaredStatus creates a strict mode expectation as for response's status code
+// being declared in the spec. The keys are the spec response keys,
+// such as "200", "4XX" or "default".
+func DeclaredStatus(opID string, keys []string, drift *contract.DriftReport, log contract.Logger) contract.Expectation {
+	log.Expecting("status declared as one of", strings.Join(keys, ", "))
+
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return false
+		}
+
+		status := strconv.Itoa(result.HTTPResponse.StatusCode)
+
+		for _, key := range keys {
+			key = strings.ToUpper(key)
+
+			if key == "DEFAULT" || key == status || (len(key) == 3 && strings.HasSuffix(key, "XX") && key[0] == status[0]) {
+				return true
+			}
+		}
+
+		d := contract.Drift{
+			Operation: opID,
+			Kind:      "status",
+			Subject:   status,
+		}
+
+		drift.Add(d)
+		log.ResponseHasDrift(d)
+
+		return false
+	}
+}
+
+// DeclaredHeaders creates a strict mode expectation which reports
+// response headers not declared in the spec. It never fails,
+// since undeclared headers are not necessarily an error.
+func DeclaredHeaders(opID string, headers []string, drift *contract.DriftReport, log contract.Logger) contract.Expectation {
+	declared := map[string]bool{}
+
+	for _, h := range append(headers, StandardHeaders...) {
+		declared[http.CanonicalHeaderKey(h)] = true
+	}
+
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil {
+			return true
+		}
+
+		names := []string{}
+		for name := range result.HTTPResponse.Header {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !declared[http.CanonicalHeaderKey(name)] {
+				d := contract.Drift{
+					Operation: opID,
+					Kind:      "header",
+					Subject:   name,
+				}
+
+				drift.Add(d)
+				log.ResponseHasDrift(d)
+			}
+		}
+
+		return true
+	}
+}
+
+// DeclaredProperties creates a strict mode expectation as for JSON response
+// bodies to have only the properties declared in the schema,
+// and none of the writeOnly ones.
+func DeclaredProperties(opID string, schema *api.Schema, drift *contract.DriftReport, log contract.Logger) contract.Expectation {
+	return func(result *contract.OperationResult) bool {
+		if result.HTTPResponse == nil || result.Size() == 0 {
+			return true
+		}
+
+		if !api.ParseMediaType(result.HTTPResponse.Header.Get("Content-Type")).IsJSON() {
+			return true
+		}
+
+		doc, err := test.Document(result)
+		if err != nil {
+			// Malformed bodies are reported by the content schema expectation.
+			return true
+		}
+
+		success := true
+
+		for _, pd := range test.UndeclaredProperties(doc, schema) {
+			d := contract.Drift{
+				Operation: opID,
+				Kind:      "property",
+				Subject:   pd.Path,
+				Details:   pd.Details,
+			}
+
+			drift.Add(d)
+			log.ResponseHasDrift(d)
+			success = false
+		}
+
+		return success
+	}
+}
+
+// ReadOnlyBody creates a strict mode expectation as for the request body
+// not having any of the properties declared as readOnly in the schema.
+func ReadOnlyBody(opID string, schema *api.Schema, body contract.Set, drift *contract.DriftReport, log contract.Logger) contract.Expectation {
+	readOnly := map[string]bool{}
+	for _, name := range test.ReadOnlyProperties(schema) {
+		readOnly[name] = true
+	}
+
+	return func(result *contract.OperationResult) bool {
+		success := true
+
+		for p := range body.Iterate() {
+			if readOnly[p.N] {
+				d := contract.Drift{
+					Operation: opID,
+					Kind:      "request property",
+					Subject:   p.N,
+					Details:   "readOnly",
+				}
+
+				drift.Add(d)
+				log.ResponseHasDrift(d)
+				success = false
+			}
+		}
+
+		return success
+	}
+}
@@ -0,0 +1,114 @@
+package expect_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test/expect"
+)
+
+func Test_DeclaredStatus(T *testing.T) {
+	log := log.New("plain", 0)
+	result := func(status int) *contract.OperationResult {
+		return &contract.OperationResult{
+			HTTPResponse: &http.Response{
+				StatusCode: status,
+			},
+		}
+	}
+
+	T.Run("Declared", func(T *testing.T) {
+		drift := contract.NewDriftReport()
+		assert.True(T, expect.DeclaredStatus("op", []string{"200", "4XX"}, drift, log)(result(200)))
+		assert.True(T, expect.DeclaredStatus("op", []string{"200", "4XX"}, drift, log)(result(404)))
+		assert.True(T, expect.DeclaredStatus("op", []string{"default"}, drift, log)(result(500)))
+		assert.Empty(T, drift.Drifts())
+	})
+
+	T.Run("Undeclared", func(T *testing.T) {
+		drift := contract.NewDriftReport()
+		assert.False(T, expect.DeclaredStatus("op", []string{"200", "4XX"}, drift, log)(result(500)))
+		assert.Equal(T, []contract.Drift{{Operation: "op", Kind: "status", Subject: "500"}}, drift.Drifts())
+	})
+}
+
+func Test_DeclaredHeaders(T *testing.T) {
+	log := log.New("plain", 0)
+	drift := contract.NewDriftReport()
+	result := &contract.OperationResult{
+		HTTPResponse: &http.Response{
+			Header: http.Header{
+				"Content-Type":   []string{"application/json"},
+				"X-Rate-Limit":   []string{"100"},
+				"X-Undocumented": []string{"yes"},
+			},
+		},
+	}
+
+	assert.True(T, expect.DeclaredHeaders("op", []string{"x-rate-limit"}, drift, log)(result))
+	assert.Equal(T, []contract.Drift{{Operation: "op", Kind: "header", Subject: "X-Undocumented"}}, drift.Drifts())
+}
+
+func Test_DeclaredProperties(T *testing.T) {
+	log := log.New("plain", 0)
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{"type": "integer"},
+			},
+		},
+	}
+
+	result := func(body string) *contract.OperationResult {
+		return &contract.OperationResult{
+			HTTPResponse: &http.Response{
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			},
+			ResponseBytes: []byte(body),
+		}
+	}
+
+	T.Run("Declared", func(T *testing.T) {
+		drift := contract.NewDriftReport()
+		assert.True(T, expect.DeclaredProperties("op", schema, drift, log)(result(`{"id": 1}`)))
+		assert.Empty(T, drift.Drifts())
+	})
+
+	T.Run("Undeclared", func(T *testing.T) {
+		drift := contract.NewDriftReport()
+		assert.False(T, expect.DeclaredProperties("op", schema, drift, log)(result(`{"id": 1, "extra": true}`)))
+		assert.Equal(T, []contract.Drift{{Operation: "op", Kind: "property", Subject: "extra"}}, drift.Drifts())
+	})
+}
+
+func Test_ReadOnlyBody(T *testing.T) {
+	log := log.New("plain", 0)
+	drift := contract.NewDriftReport()
+	schema := &api.Schema{
+		JSONSchema: api.JSONSchema{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":   map[string]interface{}{"type": "integer", "readOnly": true},
+				"name": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	src := params.NewMemorySource("test")
+	src.Add("id", "1")
+	src.Add("name", "Rex")
+
+	body := params.Body(log)
+	body.Load(src)
+
+	assert.False(T, expect.ReadOnlyBody("op", schema, body, drift, log)(&contract.OperationResult{}))
+	assert.Equal(T, []contract.Drift{{Operation: "op", Kind: "request property", Subject: "id", Details: "readOnly"}}, drift.Drifts())
+}
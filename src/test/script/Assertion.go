@@ -0,0 +1,69 @@
+package script
+
+import (
+	"net/http"
+
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// AssertionID is the operation ID of the assertion nodes.
+const AssertionID = "assert"
+
+// Assertion is the operation of the script nodes which make no requests,
+// but compare the results of other nodes, see OperationRef.Assert.
+// It's result tells whether the assertions hold.
+type Assertion struct {
+	*api.OperationPrototype
+
+	name string
+}
+
+// NewAssertion creates a new Assertion instance for the named script node.
+func NewAssertion(name string, log contract.Logger) *Assertion {
+	return &Assertion{
+		OperationPrototype: api.NewOperationPrototype(log),
+		name:               name,
+	}
+}
+
+// ID returns the assertion operation ID.
+func (op *Assertion) ID() string {
+	return AssertionID
+}
+
+// Name returns the script node name.
+func (op *Assertion) Name() string {
+	return op.name
+}
+
+// Description returns an empty string.
+func (op *Assertion) Description() string {
+	return ""
+}
+
+// Method returns an empty string, as assertions make no requests.
+func (op *Assertion) Method() string {
+	return ""
+}
+
+// Path returns an empty string, as assertions make no requests.
+func (op *Assertion) Path() string {
+	return ""
+}
+
+// Resolve returns nil, as there is no spec operation to resolve the data from.
+func (op *Assertion) Resolve() contract.DataResolver {
+	return nil
+}
+
+// GetRequest returns an error, as assertions make no requests.
+func (op *Assertion) GetRequest() (*http.Request, error) {
+	return nil, errors.Oops("The '"+op.name+"' assertion node makes no requests.", nil)
+}
+
+// Clone creates a new Assertion instance with it's own data, result & logger.
+func (op *Assertion) Clone() contract.Operation {
+	return NewAssertion(op.name, op.Log)
+}
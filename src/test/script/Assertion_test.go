@@ -0,0 +1,114 @@
+package script
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+const assertionTestScript = `
+specs:
+  service: ../../../spec/test/oas3.yaml
+operations:
+  getPet:
+    operationId: service.getPetById
+    use:
+      path:
+        petId: 5
+  getUser:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: gopher
+  check:
+    assert:
+      - "#getPet.response.owner == #getUser.response.username"
+      - "#getPet.response.price <= sum(#getUser.response.wallets, 'balance')"
+`
+
+func Test_Assertion(T *testing.T) {
+	build := func(T *testing.T, source string) (*Script, *ExecutionGraph, error) {
+		s := loadTestScriptSource(T, source, "")
+		assert.NotNil(T, s)
+
+		graph := NewExecutionGraph(log.NewPlain(0))
+		return s, graph, s.Build(graph)
+	}
+
+	respond := func(n *ExecutionNode, body string) {
+		result := n.Operation.Result()
+		result.HTTPResponse = &http.Response{StatusCode: http.StatusOK}
+		result.ResponseBytes = []byte(body)
+		n.Result = result
+	}
+
+	run := func(T *testing.T, wallet string) *ExecutionNode {
+		s, graph, err := build(T, assertionTestScript)
+		assert.Nil(T, err)
+
+		respond(graph.Node("getPet").(*ExecutionNode), `{"owner": "gopher", "price": 99.5}`)
+		respond(graph.Node("getUser").(*ExecutionNode), `{"username": "gopher", "wallets": [{"balance": 50}, {"balance": `+wallet+`}]}`)
+
+		ex := NewExecutor(log.NewPlain(0), s)
+		ex.Sequential = true
+
+		check := graph.Node("check").(*ExecutionNode)
+		ex.Run(graph, check)
+
+		return check
+	}
+
+	T.Run("Graph", func(T *testing.T) {
+		_, graph, err := build(T, assertionTestScript)
+		assert.Nil(T, err)
+
+		check := graph.Node("check").(*ExecutionNode)
+		assert.Equal(T, AssertionID, check.Operation.ID())
+		assert.Len(T, check.Assert, 2)
+		assert.Equal(T, []string{DependencyAssert}, graph.Reasons("check", "getPet"))
+		assert.Equal(T, []string{DependencyAssert}, graph.Reasons("check", "getUser"))
+	})
+
+	T.Run("Pass", func(T *testing.T) {
+		check := run(T, "49.5")
+		assert.True(T, check.Result.Success)
+		assert.Nil(T, check.Result.HTTPRequest)
+	})
+
+	T.Run("Fail", func(T *testing.T) {
+		assert.False(T, run(T, "49.4").Result.Success)
+	})
+
+	T.Run("Invalid", func(T *testing.T) {
+		for _, op := range []string{
+			"check:\n    assert: [\"#nope.response.id == 1\"]",
+			"check:\n    assert: [\"#getPet.response.id ==\"]",
+			"check:\n    forEach: \"#getPet.response.tags\"\n    assert: [\"${item} == 1\"]",
+			"check:\n    operationId: service.getPetById\n    assert: [\"#getPet.response.id == 1\"]",
+		} {
+			_, _, err := build(T, `
+specs:
+  service: ../../../spec/test/oas3.yaml
+operations:
+  getPet:
+    operationId: service.getPetById
+  `+op)
+			assert.NotNil(T, err, op)
+		}
+	})
+
+	T.Run("Item", func(T *testing.T) {
+		_, _, err := build(T, `
+specs:
+  service: ../../../spec/test/oas3.yaml
+operations:
+  getPet:
+    operationId: service.getPetById
+  check:
+    assert: ["#getPet.response.id == ${item}"]`)
+		assert.NotNil(T, err)
+		assert.Contains(T, err.Error(), "${item} & ${index} aren't available in assertion nodes")
+	})
+}
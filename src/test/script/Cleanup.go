@@ -0,0 +1,240 @@
+package script
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// CleanupAuto is the script cleanup mode which deletes
+// the resources created by the script after it's execution.
+const CleanupAuto = "auto"
+
+// CleanupTarget is a resource created by a script node,
+// along with the DELETE operation to delete it with.
+type CleanupTarget struct {
+	Node      *ExecutionNode
+	Operation contract.Operation
+	Params    map[string]string
+}
+
+// Key identifies the deleted resource.
+func (t *CleanupTarget) Key() string {
+	return cleanupKey(t.Operation, t.Params)
+}
+
+func cleanupKey(op contract.Operation, pathParams map[string]string) string {
+	values := url.Values{}
+	for k, v := range pathParams {
+		values.Set(k, v)
+	}
+
+	return op.ID() + "?" + values.Encode()
+}
+
+// GetCleanupGraph builds and returns a graph of the DELETE operations
+// for the resources created by the executed script, when it has `cleanup: auto`.
+// Resources the script has deleted itself are left alone.
+// Returns nil when there is nothing to clean up.
+func (script *Script) GetCleanupGraph() gcontract.Graph {
+	if script.Cleanup != CleanupAuto || script.graph == nil {
+		return nil
+	}
+
+	executed := executedNodes(script.graph)
+	if script.teardown != nil {
+		executed = append(executed, executedNodes(script.teardown)...)
+	}
+
+	deletes := map[string][]contract.Operation{}
+	deleted := map[string]bool{}
+
+	for _, n := range executed {
+		if n.Result != nil && n.Result.Success && n.Result.HTTPRequest != nil && n.Operation.Method() == http.MethodDelete {
+			if pathParams, ok := api.MatchPath(n.Operation.Path(), n.Result.HTTPRequest.URL.Path); ok {
+				deleted[cleanupKey(n.Operation, pathParams)] = true
+			}
+		}
+	}
+
+	graph := NewExecutionGraph(script.Log)
+
+	for _, n := range executed {
+		if _, ok := deletes[n.Spec]; !ok {
+			deletes[n.Spec] = deleteOperations(script.GetSpec(n.Spec))
+		}
+
+		t := FindCleanupTarget(n, deletes[n.Spec])
+		if t == nil || deleted[t.Key()] {
+			continue
+		}
+
+		deleted[t.Key()] = true
+
+		// The resource is deleted the way it was created: with the same query,
+		// headers & security, the defaults included.
+		opRef := &OperationRef{
+			OperationID: n.Spec + "." + t.Operation.ID(),
+			Use: OperationDataUse{
+				Query:    n.Use.Query,
+				Headers:  n.Use.Headers,
+				Security: n.Use.Security,
+			},
+		}
+
+		cn := NewExecutionNode(script.NewOperation(opRef.OperationID), "cleanup."+string(n.ID()), opRef, script.Log)
+		cn.Spec = n.Spec
+		cn.Host = n.Host
+		cn.Row = n.Row
+		cn.Sec = n.Sec
+
+		src := params.NewMemorySource("cleanup")
+		for k, v := range t.Params {
+			src.Add(k, v)
+		}
+
+		cn.Data.URL.Load(src)
+		cn.Data.Query.Load(n.Data.Query)
+		cn.Data.Headers.Load(n.Data.Headers)
+
+		graph.AddNode(cn)
+	}
+
+	if graph.Nodes().Count() == 0 {
+		return nil
+	}
+
+	return graph
+}
+
+// executedNodes returns the graph nodes in the execution order,
+// with forEach nodes followed by their items.
+func executedNodes(graph gcontract.Graph) []*ExecutionNode {
+	order, err := ExecutionOrder(graph)
+	if err != nil {
+		return nil
+	}
+
+	nodes := []*ExecutionNode{}
+	for _, n := range order {
+		if n.ForEach != nil {
+			nodes = append(nodes, n.Items...)
+		} else {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// deleteOperations returns the DELETE operations of the spec.
+func deleteOperations(spec contract.OperationAccess) []contract.Operation {
+	ops := []contract.Operation{}
+	if spec == nil {
+		return ops
+	}
+
+	for op := range spec.Operations() {
+		if op.Method() == http.MethodDelete {
+			ops = append(ops, op)
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].ID() < ops[j].ID()
+	})
+
+	return ops
+}
+
+// FindCleanupTarget finds the resource created by the node, if any.
+// A resource is created when a POST or PUT operation responds with 201.
+// The DELETE operation path must either match the response Location header,
+// or be the creating operation path (POST) followed by a parameter,
+// taken from the same-named response body field, or "id".
+// A PUT resource is deleted by the same path it was put to.
+func FindCleanupTarget(n *ExecutionNode, deletes []contract.Operation) *CleanupTarget {
+	res := n.Result
+	if res == nil || res.HTTPRequest == nil || res.HTTPResponse == nil || res.HTTPResponse.StatusCode != http.StatusCreated {
+		return nil
+	}
+
+	method := n.Operation.Method()
+	if method != http.MethodPost && method != http.MethodPut {
+		return nil
+	}
+
+	if loc := res.HTTPResponse.Header.Get("Location"); loc != "" {
+		if u, err := url.Parse(loc); err == nil {
+			var t *CleanupTarget
+			for _, d := range deletes {
+				if pathParams, ok := api.MatchPath(d.Path(), u.Path); ok {
+					if t == nil || len(d.Path()) > len(t.Operation.Path()) {
+						t = &CleanupTarget{Node: n, Operation: d, Params: pathParams}
+					}
+				}
+			}
+
+			if t != nil {
+				return t
+			}
+		}
+	}
+
+	opPath := n.Operation.Path()
+	reqPath := res.HTTPRequest.URL.Path
+
+	for _, d := range deletes {
+		if method == http.MethodPut {
+			if d.Path() != opPath {
+				continue
+			}
+
+			if pathParams, ok := api.MatchPath(opPath, reqPath); ok {
+				return &CleanupTarget{Node: n, Operation: d, Params: pathParams}
+			}
+
+			continue
+		}
+
+		dParams := api.PathParams(d.Path())
+		if len(dParams) == 0 {
+			continue
+		}
+
+		idParam := dParams[len(dParams)-1]
+		if d.Path() != strings.TrimSuffix(opPath, "/")+"/{"+idParam+"}" {
+			continue
+		}
+
+		pathParams, ok := api.MatchPath(opPath, reqPath)
+		if !ok {
+			continue
+		}
+
+		doc, err := test.Document(res)
+		if err != nil {
+			continue
+		}
+
+		obj, _ := doc.(map[string]interface{})
+		id, ok := obj[idParam]
+		if !ok {
+			id, ok = obj["id"]
+		}
+
+		if ok && id != nil {
+			pathParams[idParam] = params.Cast(id)
+			return &CleanupTarget{Node: n, Operation: d, Params: pathParams}
+		}
+	}
+
+	return nil
+}
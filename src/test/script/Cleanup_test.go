@@ -0,0 +1,134 @@
+package script
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	kin "github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Cleanup(T *testing.T) {
+	operation := func(id string, method string, path string) contract.Operation {
+		return &openapi3.Operation{
+			RequestMethod: method,
+			RequestPath:   path,
+			SpecOp:        &kin.Operation{OperationID: id},
+		}
+	}
+
+	node := func(method string, path string, reqPath string, status int, location string, body string) *ExecutionNode {
+		n := NewExecutionNode(operation("create", method, path), "create", &OperationRef{}, log.NewPlain(0))
+		n.Result = &contract.OperationResult{
+			HTTPRequest:   &http.Request{Method: method, URL: &url.URL{Path: reqPath}},
+			HTTPResponse:  &http.Response{StatusCode: status, Header: http.Header{}},
+			ResponseBytes: []byte(body),
+		}
+
+		if location != "" {
+			n.Result.HTTPResponse.Header.Set("Location", location)
+		}
+
+		return n
+	}
+
+	deletes := []contract.Operation{
+		operation("deletePet", http.MethodDelete, "/pets/{petId}"),
+		operation("deleteOrder", http.MethodDelete, "/users/{userId}/orders/{orderId}"),
+		operation("deleteSetting", http.MethodDelete, "/settings/{name}"),
+	}
+
+	T.Run("Location", func(T *testing.T) {
+		n := node(http.MethodPost, "/pets", "/v2/pets", 201, "https://example.com/v2/pets/42", `{}`)
+
+		t := FindCleanupTarget(n, deletes)
+		assert.NotNil(T, t)
+		assert.Equal(T, "deletePet", t.Operation.ID())
+		assert.Equal(T, map[string]string{"petId": "42"}, t.Params)
+	})
+
+	T.Run("ID", func(T *testing.T) {
+		n := node(http.MethodPost, "/users/{userId}/orders", "/users/7/orders", 201, "", `{"id": 9}`)
+
+		t := FindCleanupTarget(n, deletes)
+		assert.NotNil(T, t)
+		assert.Equal(T, "deleteOrder", t.Operation.ID())
+		assert.Equal(T, map[string]string{"userId": "7", "orderId": "9"}, t.Params)
+	})
+
+	T.Run("Named ID", func(T *testing.T) {
+		n := node(http.MethodPost, "/pets", "/pets", 201, "", `{"id": 1, "petId": 42}`)
+
+		t := FindCleanupTarget(n, deletes)
+		assert.NotNil(T, t)
+		assert.Equal(T, map[string]string{"petId": "42"}, t.Params)
+	})
+
+	T.Run("PUT", func(T *testing.T) {
+		n := node(http.MethodPut, "/settings/{name}", "/settings/theme", 201, "", ``)
+
+		t := FindCleanupTarget(n, deletes)
+		assert.NotNil(T, t)
+		assert.Equal(T, "deleteSetting", t.Operation.ID())
+		assert.Equal(T, map[string]string{"name": "theme"}, t.Params)
+	})
+
+	T.Run("Not created", func(T *testing.T) {
+		assert.Nil(T, FindCleanupTarget(node(http.MethodPost, "/pets", "/pets", 200, "", `{"id": 1}`), deletes))
+		assert.Nil(T, FindCleanupTarget(node(http.MethodGet, "/pets", "/pets", 201, "", `{"id": 1}`), deletes))
+		assert.Nil(T, FindCleanupTarget(node(http.MethodPost, "/stores", "/stores", 201, "", `{"id": 1}`), deletes))
+	})
+
+	T.Run("Graph", func(T *testing.T) {
+		s := loadTestScriptSource(T, `
+specs:
+  service: ../../../spec/test/oas3.yaml
+cleanup: auto
+defaults:
+  use:
+    headers:
+      X-Api-Version: "2"
+operations:
+  update:
+    operationId: service.updateUser
+    use:
+      path:
+        username: gopher
+      query:
+        tenant: acme
+      headers:
+        X-Tenant: acme
+`, "")
+		assert.NotNil(T, s)
+
+		graph := s.GetExecutionGraph().(*ExecutionGraph)
+		update := graph.Node("update").(*ExecutionNode)
+		update.Result = &contract.OperationResult{
+			Success:      true,
+			HTTPRequest:  &http.Request{Method: http.MethodPut, URL: &url.URL{Path: "/user/gopher"}},
+			HTTPResponse: &http.Response{StatusCode: http.StatusCreated, Header: http.Header{}},
+		}
+
+		cleanup := s.GetCleanupGraph()
+		assert.NotNil(T, cleanup)
+
+		cn := cleanup.Node("cleanup.update").(*ExecutionNode)
+		assert.Equal(T, "deleteUser", cn.Operation.ID())
+
+		values := func(set contract.Set) map[string]string {
+			res := map[string]string{}
+			for p := range set.Iterate() {
+				res[p.N] = p.V()
+			}
+
+			return res
+		}
+
+		assert.Equal(T, map[string]string{"tenant": "acme"}, values(cn.Data.Query))
+		assert.Equal(T, map[string]string{"X-Tenant": "acme", "X-Api-Version": "2"}, values(cn.Data.Headers))
+	})
+}
@@ -0,0 +1,130 @@
+package script
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// ScriptDataset corresponds to the 'dataset' block of a script file.
+// The script is executed once per row, the rows are either inline,
+// or come from a CSV, JSON or YAML file, or both.
+// Parallelism is the maximal number of rows executed at once, 1 by default.
+type ScriptDataset struct {
+	Rows        []map[string]interface{} `yaml:"rows"`
+	File        string                   `yaml:"file"`
+	Parallelism int                      `yaml:"parallelism"`
+}
+
+// DatasetRow is a single dataset row, field values by names.
+type DatasetRow map[string]string
+
+// Vars returns the row fields as script variables, like ${row.email}.
+func (row DatasetRow) Vars() Vars {
+	vars := Vars{}
+	for k, v := range row {
+		vars[RowVar+"."+k] = v
+	}
+
+	return vars
+}
+
+// Load returns the inline rows followed by the file ones.
+func (ds *ScriptDataset) Load() ([]DatasetRow, error) {
+	rows := []DatasetRow{}
+
+	for _, row := range ds.Rows {
+		rows = append(rows, datasetRow(row))
+	}
+
+	if ds.File != "" {
+		fileRows, err := LoadDataset(ds.File)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, fileRows...)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.Oops("The script dataset has no rows.", nil)
+	}
+
+	return rows, nil
+}
+
+// LoadDataset loads dataset rows from a file. CSV files must have
+// a header line with field names, JSON & YAML files must contain
+// an array of objects.
+func LoadDataset(path string) ([]DatasetRow, error) {
+	fileData, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Oops("Failed to read the dataset file '"+path+"'.", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		records, err := csv.NewReader(strings.NewReader(string(fileData))).ReadAll()
+		if err != nil {
+			return nil, errors.Oops("Failed to parse the dataset file '"+path+"'.", err)
+		}
+
+		rows := []DatasetRow{}
+		for i := 1; i < len(records); i++ {
+			row := DatasetRow{}
+			for fi, field := range records[0] {
+				row[field] = records[i][fi]
+			}
+
+			rows = append(rows, row)
+		}
+
+		return rows, nil
+
+	case ".json":
+		data := []map[string]interface{}{}
+		if err := json.Unmarshal(fileData, &data); err != nil {
+			return nil, errors.Oops("Failed to parse the dataset file '"+path+"'.", err)
+		}
+
+		return datasetRows(data), nil
+
+	case ".yaml", ".yml":
+		data := []map[string]interface{}{}
+		if err := yaml.Unmarshal(fileData, &data); err != nil {
+			return nil, errors.Oops("Failed to parse the dataset file '"+path+"'.", err)
+		}
+
+		return datasetRows(data), nil
+	}
+
+	return nil, errors.Oops("The dataset file '"+path+"' is neither CSV, JSON nor YAML.", nil)
+}
+
+func datasetRows(data []map[string]interface{}) []DatasetRow {
+	rows := []DatasetRow{}
+	for _, row := range data {
+		rows = append(rows, datasetRow(row))
+	}
+
+	return rows
+}
+
+func datasetRow(data map[string]interface{}) DatasetRow {
+	row := DatasetRow{}
+	for k, v := range data {
+		if v == nil {
+			row[k] = ""
+		} else {
+			row[k] = params.Cast(v)
+		}
+	}
+
+	return row
+}
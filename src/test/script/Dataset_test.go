@@ -0,0 +1,77 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadTestDataset(T *testing.T, ext string, data string) ([]DatasetRow, error) {
+	file, err := ioutil.TempFile("", "oasis-dataset-*"+ext)
+	assert.Nil(T, err)
+	defer os.Remove(file.Name())
+
+	file.WriteString(data)
+	file.Close()
+
+	return LoadDataset(file.Name())
+}
+
+func Test_Dataset(T *testing.T) {
+	expected := []DatasetRow{
+		{"name": "gopher", "age": "11"},
+		{"name": "rustacean", "age": "5"},
+	}
+
+	T.Run("CSV", func(T *testing.T) {
+		rows, err := loadTestDataset(T, ".csv", "name,age\ngopher,11\nrustacean,5\n")
+		assert.Nil(T, err)
+		assert.Equal(T, expected, rows)
+	})
+
+	T.Run("JSON", func(T *testing.T) {
+		rows, err := loadTestDataset(T, ".json", `[{"name": "gopher", "age": 11}, {"name": "rustacean", "age": 5}]`)
+		assert.Nil(T, err)
+		assert.Equal(T, expected, rows)
+	})
+
+	T.Run("YAML", func(T *testing.T) {
+		rows, err := loadTestDataset(T, ".yml", "- name: gopher\n  age: 11\n- name: rustacean\n  age: 5\n")
+		assert.Nil(T, err)
+		assert.Equal(T, expected, rows)
+	})
+
+	T.Run("Unknown format", func(T *testing.T) {
+		_, err := loadTestDataset(T, ".txt", "gopher")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Inline & file rows", func(T *testing.T) {
+		file, err := ioutil.TempFile("", "oasis-dataset-*.csv")
+		assert.Nil(T, err)
+		defer os.Remove(file.Name())
+
+		file.WriteString("name,age\nrustacean,5\n")
+		file.Close()
+
+		ds := &ScriptDataset{
+			Rows: []map[string]interface{}{{"name": "gopher", "age": 11}},
+			File: file.Name(),
+		}
+
+		rows, err := ds.Load()
+		assert.Nil(T, err)
+		assert.Equal(T, expected, rows)
+	})
+
+	T.Run("Vars", func(T *testing.T) {
+		assert.Equal(T, Vars{"row.name": "gopher", "row.age": "11"}, expected[0].Vars())
+	})
+
+	T.Run("No rows", func(T *testing.T) {
+		_, err := (&ScriptDataset{}).Load()
+		assert.NotNil(T, err)
+	})
+}
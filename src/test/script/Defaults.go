@@ -0,0 +1,129 @@
+package script
+
+import "github.com/x1n13y84issmd42/oasis/src/params"
+
+// ScriptSpec is a spec used in a script. In the script file it is either
+// a path to the spec file, or an object with the path & the spec defaults.
+type ScriptSpec struct {
+	Path     string             `yaml:"path"`
+	Defaults *OperationDefaults `yaml:"defaults"`
+}
+
+// UnmarshalYAML reads a ScriptSpec from either a string or an object.
+func (spec *ScriptSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&spec.Path); err == nil {
+		return nil
+	}
+
+	type plain ScriptSpec
+	return unmarshal((*plain)(spec))
+}
+
+// OperationDefaults are the 'use' & 'expect' values applied to every script
+// operation, or to every operation of a spec. Operations' own values
+// take precedence over them.
+type OperationDefaults struct {
+	Use    OperationDataUse    `yaml:"use"`
+	Expect OperationDataExpect `yaml:"expect"`
+}
+
+// Apply merges the defaults into the opRef. Values referencing operations,
+// as a whole or from a template, are not applied when those operations depend
+// on the opRef, as told by dependsOn, since that would make a cycle.
+// Referencing the opRef itself counts as such a dependency.
+func (defaults *OperationDefaults) Apply(opRef *OperationRef, dependsOn func(op2RefID string) bool) {
+	if defaults == nil {
+		return
+	}
+
+	use, expect := &opRef.Use, &opRef.Expect
+
+	use.Path = defaults.Merge(use.Path, defaults.Use.Path, dependsOn)
+	use.Query = defaults.Merge(use.Query, defaults.Use.Query, dependsOn)
+	use.Headers = defaults.Merge(use.Headers, defaults.Use.Headers, dependsOn)
+	use.Body = defaults.Merge(use.Body, defaults.Use.Body, dependsOn)
+
+	if use.Security == "" {
+		use.Security = defaults.Use.Security
+	}
+
+	if use.CT == "" {
+		use.CT = defaults.Use.CT
+	}
+
+	if use.Status == 0 {
+		use.Status = defaults.Use.Status
+	}
+
+	expect.Body = defaults.Merge(expect.Body, defaults.Expect.Body, dependsOn)
+	expect.Headers = defaults.Merge(expect.Headers, defaults.Expect.Headers, dependsOn)
+
+	if expect.CT == "" {
+		expect.CT = defaults.Expect.CT
+	}
+
+	if expect.Status == 0 {
+		expect.Status = defaults.Expect.Status
+	}
+
+	if expect.MinSize == 0 {
+		expect.MinSize = defaults.Expect.MinSize
+	}
+
+	if expect.MaxSize == 0 {
+		expect.MaxSize = defaults.Expect.MaxSize
+	}
+
+	if expect.SHA256 == "" {
+		expect.SHA256 = defaults.Expect.SHA256
+	}
+
+	if expect.MD5 == "" {
+		expect.MD5 = defaults.Expect.MD5
+	}
+}
+
+// Merge adds the default values missing in m, except those referencing
+// the operations which dependsOn tells depend on the operation of m.
+func (defaults *OperationDefaults) Merge(m OperationDataMap, dm OperationDataMap, dependsOn func(op2RefID string) bool) OperationDataMap {
+	if len(dm) == 0 {
+		return m
+	}
+
+	if m == nil {
+		m = OperationDataMap{}
+	}
+
+	for k, v := range dm {
+		if _, ok := m[k]; ok {
+			continue
+		}
+
+		cyclic := false
+		for _, refID := range references(v) {
+			cyclic = cyclic || dependsOn(refID)
+		}
+
+		if !cyclic {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+// references returns the IDs of the operations referenced in v,
+// either as a whole, or from a template.
+func references(v string) []string {
+	if isref, refID, _ := Dereference(v); isref {
+		return []string{refID}
+	}
+
+	if params.IsTemplate(v) {
+		if tpl, err := params.ParseTemplate(v); err == nil {
+			return tpl.References()
+		}
+	}
+
+	return nil
+}
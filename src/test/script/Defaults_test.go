@@ -0,0 +1,139 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/go-yaml/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+const defaultsTestScript = `
+specs:
+  auth: spec/auth.yaml
+  service:
+    path: spec/service.yaml
+    defaults:
+      use:
+        headers:
+          Content-Type: application/json
+      expect:
+        status: 201
+defaults:
+  use:
+    security: token
+    headers:
+      Content-Type: application/x-www-form-urlencoded
+      Authorization: "Bearer {{#login.response.token}}"
+    query:
+      access_token: "#login.response.token"
+  expect:
+    status: 200
+operations:
+  login:
+    operationId: auth.login
+  create:
+    operationId: service.create
+    use:
+      query:
+        type: credit
+  get:
+    operationId: service.get
+    use:
+      security: basic
+      query:
+        access_token: local
+    expect:
+      status: 200
+`
+
+func Test_Defaults(T *testing.T) {
+	script := &Script{}
+	assert.Nil(T, yaml.Unmarshal([]byte(defaultsTestScript), script))
+
+	T.Run("Specs", func(T *testing.T) {
+		assert.Equal(T, "spec/auth.yaml", script.Specs["auth"].Path)
+		assert.Nil(T, script.Specs["auth"].Defaults)
+		assert.Equal(T, "spec/service.yaml", script.Specs["service"].Path)
+		assert.NotNil(T, script.Specs["service"].Defaults)
+	})
+
+	script.ApplyDefaults()
+
+	T.Run("Script defaults", func(T *testing.T) {
+		login := script.Operations["login"]
+
+		assert.Equal(T, "token", login.Use.Security)
+		assert.Equal(T, "application/x-www-form-urlencoded", login.Use.Headers["Content-Type"])
+		assert.Equal(T, int64(200), login.Expect.Status)
+
+		// A node can't depend on itself.
+		assert.NotContains(T, login.Use.Query, "access_token")
+		assert.NotContains(T, login.Use.Headers, "Authorization")
+	})
+
+	T.Run("Spec defaults", func(T *testing.T) {
+		create := script.Operations["create"]
+
+		assert.Equal(T, "application/json", create.Use.Headers["Content-Type"])
+		assert.Equal(T, "#login.response.token", create.Use.Query["access_token"])
+		assert.Equal(T, "Bearer {{#login.response.token}}", create.Use.Headers["Authorization"])
+		assert.Equal(T, "credit", create.Use.Query["type"])
+		assert.Equal(T, int64(201), create.Expect.Status)
+	})
+
+	T.Run("Node values", func(T *testing.T) {
+		get := script.Operations["get"]
+
+		assert.Equal(T, "basic", get.Use.Security)
+		assert.Equal(T, "local", get.Use.Query["access_token"])
+		assert.Equal(T, int64(200), get.Expect.Status)
+	})
+
+	T.Run("Cycles", func(T *testing.T) {
+		script := &Script{}
+		assert.Nil(T, yaml.Unmarshal([]byte(`
+defaults:
+  use:
+    query:
+      access_token: "#pinLogin.response.token"
+    headers:
+      X-Request-ID: "{{#sendOtp.response.id}}"
+operations:
+  sendOtp:
+    operationId: auth.sendOtp
+  pinLogin:
+    operationId: auth.pinLogin
+    use:
+      body:
+        otp: "#sendOtp.response.otp"
+  profile:
+    operationId: service.profile
+  legacy:
+    operationId: service.legacy
+    defaults: false
+  check:
+    assert:
+      - "#profile.response.id == 1"
+teardown:
+  logout:
+    operationId: auth.logout
+`), script))
+
+		script.ApplyDefaults()
+
+		// pinLogin depends on sendOtp, so sendOtp can't depend on pinLogin.
+		assert.NotContains(T, script.Operations["sendOtp"].Use.Query, "access_token")
+		assert.NotContains(T, script.Operations["sendOtp"].Use.Headers, "X-Request-ID")
+		assert.NotContains(T, script.Operations["pinLogin"].Use.Query, "access_token")
+		assert.Equal(T, "{{#sendOtp.response.id}}", script.Operations["pinLogin"].Use.Headers["X-Request-ID"])
+
+		assert.Equal(T, "#pinLogin.response.token", script.Operations["profile"].Use.Query["access_token"])
+		assert.Equal(T, "#pinLogin.response.token", script.Teardown["logout"].Use.Query["access_token"])
+
+		assert.Empty(T, script.Operations["legacy"].Use.Query)
+		assert.Empty(T, script.Operations["check"].Use.Query)
+
+		assert.True(T, script.DependsOn(script.Operations, "profile", "sendOtp"))
+		assert.False(T, script.DependsOn(script.Operations, "sendOtp", "profile"))
+	})
+}
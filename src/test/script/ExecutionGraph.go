@@ -2,10 +2,12 @@ package script
 
 import (
 	"sync"
+	"time"
 
 	gog "github.com/x1n13y84issmd42/gog/graph"
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
 	"github.com/x1n13y84issmd42/oasis/src/params"
 )
 
@@ -20,27 +22,81 @@ type ExecutionNode struct {
 	Mutex      sync.Mutex
 	Result     *contract.OperationResult
 	Use        *OperationDataUse
-	Expect     *OperationDataUse
+	Expect     *OperationDataExpect
 	ExpectBody *params.BodyParameters
+
+	// Spec is the name of the operation's spec in the script.
+	// Host overrides the spec host when set, see Script.Hosts.
+	Spec string
+	Host string
+
+	// Row is the dataset row number, zero without a dataset.
+	// Sec are the security parameters of the node's script iteration.
+	Row int
+	Sec map[string]*contract.SecurityAccess
+
+	// ForEach is set for the nodes which execute once per array element,
+	// the executed item nodes are collected in Items.
+	ForEach *ForEach
+	Items   []*ExecutionNode
+
+	// If is the condition to execute the node on, evaluated in IfContext.
+	// OnSkipped is either OnSkippedSkip or OnSkippedRun.
+	If        *params.Condition
+	IfContext *params.TemplateContext
+	OnSkipped string
+
+	// Assert are the assertions evaluated in AssertContext
+	// instead of making a request, see Script.SetupAssertions().
+	Assert        []*params.Condition
+	AssertContext *params.TemplateContext
+
+	// Output collects the node's log output to be printed
+	// in the execution order once the node is done.
+	Output   *log.BufferedStdOut
+	Done     chan struct{}
+	Skipped  bool
+	Duration time.Duration
+
+	// Conditional is set for the nodes skipped because of a false condition,
+	// their own or a dependency's one, or missing results, rather than a failure.
+	Conditional bool
+
+	// Requires are the nodes of another graph whose results the node uses,
+	// it is skipped when they have no results.
+	Requires []*ExecutionNode
+
+	// Restored is set for the nodes which results are restored
+	// from a run state rather than executed, see RunState.Restore().
+	Restored bool
 }
 
+// The ways to react to dependencies skipped by their conditions:
+// either skip the node too, or run it anyway.
+const (
+	OnSkippedSkip = "skip"
+	OnSkippedRun  = "run"
+)
+
 // NewExecutionNode creates a new ExecutionNode instance.
-func NewExecutionNode(op contract.Operation, opRefID string, opRef *OperationRef, log contract.Logger) *ExecutionNode {
+func NewExecutionNode(op contract.Operation, opRefID string, opRef *OperationRef, logger contract.Logger) *ExecutionNode {
 	n := &ExecutionNode{
 		Operation: op,
 		OpRefID:   opRefID,
 	}
 
 	n.Mutex = sync.Mutex{}
+	n.Output = log.NewBufferedStdOut()
+	n.Done = make(chan struct{})
 
-	n.Data.URL = params.URL("", log)
-	n.Data.Query = params.Query(log)
-	n.Data.Headers = params.Headers(log)
-	n.Data.Body = params.Body(log)
+	n.Data.URL = params.URL("", logger)
+	n.Data.Query = params.Query(logger)
+	n.Data.Headers = params.Headers(logger)
+	n.Data.Body = params.Body(logger)
 
 	n.Use = &opRef.Use
 	n.Expect = &opRef.Expect
-	n.ExpectBody = params.Body(log)
+	n.ExpectBody = params.Body(logger)
 
 	return n
 }
@@ -60,10 +116,23 @@ func (node *ExecutionNode) Unlock() {
 	node.Mutex.Unlock()
 }
 
+// The reasons of the dependencies between the execution graph nodes.
+const (
+	DependencyData      = "data"
+	DependencyAfter     = "after"
+	DependencySecurity  = "security"
+	DependencyCondition = "condition"
+	DependencyForEach   = "forEach"
+	DependencyAssert    = "assert"
+)
+
 // ExecutionGraph is a graph representing interdependencies between operations.
+// An edge from A to B means that A depends on B, for the reasons in reasons.
 type ExecutionGraph struct {
 	contract.EntityTrait
 	*gog.DGraph
+
+	reasons map[[2]gcontract.NodeID][]string
 }
 
 // NewExecutionGraph creates a new OperationGraph instance.
@@ -71,5 +140,26 @@ func NewExecutionGraph(log contract.Logger) *ExecutionGraph {
 	return &ExecutionGraph{
 		EntityTrait: contract.Entity(log),
 		DGraph:      gog.NewDGraph(),
+		reasons:     map[[2]gcontract.NodeID][]string{},
+	}
+}
+
+// AddDependency adds an edge from the v1 node to the v2 one it depends on,
+// and remembers the reason of it.
+func (graph *ExecutionGraph) AddDependency(v1 gcontract.NodeID, v2 gcontract.NodeID, reason string) {
+	graph.AddEdge(v1, v2)
+
+	key := [2]gcontract.NodeID{v1, v2}
+	for _, r := range graph.reasons[key] {
+		if r == reason {
+			return
+		}
 	}
+
+	graph.reasons[key] = append(graph.reasons[key], reason)
+}
+
+// Reasons returns the reasons the v1 node depends on the v2 one for.
+func (graph *ExecutionGraph) Reasons(v1 gcontract.NodeID, v2 gcontract.NodeID) []string {
+	return graph.reasons[[2]gcontract.NodeID{v1, v2}]
 }
@@ -1,19 +1,50 @@
 package script
 
 import (
-	"sync"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
 	"github.com/x1n13y84issmd42/oasis/src/test"
 	"github.com/x1n13y84issmd42/oasis/src/test/expect"
 )
 
 // Executor executes an ExecutionGraph that comes from a script.
+// Nodes are executed concurrently by a pool of workers, but their log output
+// is collected per node and printed in the execution order, followed by a summary.
 type Executor struct {
 	contract.EntityTrait
 
 	Script contract.Script
+
+	// Drift is set in strict mode, see contract.DataResolver.Strict().
+	Drift *contract.DriftReport
+
+	// Workers is the maximal number of nodes executed at once,
+	// unlimited when zero. Sequential executes nodes one by one
+	// in the execution order, printing their output right away.
+	Workers    int
+	Sequential bool
+
+	// Rows is the maximal number of dataset rows executed at once,
+	// unlimited when zero.
+	Rows int
+
+	// Output receives the nodes' output instead of stdout when set,
+	// like when scripts are executed in parallel in a suite.
+	Output contract.LogOutput
+
+	// Summary is the summary of the executed graph, see Executor.Execute().
+	Summary []contract.ScriptNodeSummary
+
+	// State records the outcomes of the executed graph nodes when set,
+	// and is saved as they get done.
+	State *RunState
 }
 
 // NewExecutor creates a new Executor instance.
@@ -24,100 +55,479 @@ func NewExecutor(logger contract.Logger, script contract.Script) *Executor {
 	}
 }
 
-// Execute executes.
-func (ex Executor) Execute(graph gcontract.Graph) {
-	success := true
-	results := make(contract.OperationResults)
+// ExecutionOrder returns the graph nodes in a topological order,
+// dependencies first. Independent nodes are ordered by their dataset rows
+// & IDs, so the order is the same for the same graph.
+func ExecutionOrder(graph gcontract.Graph) ([]*ExecutionNode, error) {
+	deps := map[gcontract.NodeID]int{}
+	dependents := map[gcontract.NodeID][]*ExecutionNode{}
+	ready := []*ExecutionNode{}
+
+	for _n := range graph.Nodes().Range() {
+		n := _n.(*ExecutionNode)
+		deps[n.ID()] = 0
+
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			deps[n.ID()]++
+			dependents[an.ID()] = append(dependents[an.ID()], n)
+		}
 
-	wg := sync.WaitGroup{}
-	for node := range graph.Nodes().Range() {
-		wg.Add(1)
-		go ex.Walk(graph, node.(*ExecutionNode), &wg, &results)
+		if deps[n.ID()] == 0 {
+			ready = append(ready, n)
+		}
 	}
 
-	wg.Wait()
+	order := []*ExecutionNode{}
+
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if ready[i].Row != ready[j].Row {
+				return ready[i].Row < ready[j].Row
+			}
+
+			return ready[i].ID() < ready[j].ID()
+		})
+
+		n := ready[0]
+		ready = ready[1:]
+		order = append(order, n)
 
-	for nID, nRes := range results {
-		if !nRes.Success {
-			ex.Log.NOMESSAGE("\rOperation %s has failed.", nID)
+		for _, dn := range dependents[n.ID()] {
+			deps[dn.ID()]--
+			if deps[dn.ID()] == 0 {
+				ready = append(ready, dn)
+			}
+		}
+	}
+
+	if len(order) < len(deps) {
+		cyclic := []string{}
+		for nID, count := range deps {
+			if count > 0 {
+				cyclic = append(cyclic, string(nID))
+			}
+		}
+
+		sort.Strings(cyclic)
+
+		return nil, errors.Oops("The execution graph has cycles between the nodes "+strings.Join(cyclic, ", ")+".", nil)
+	}
+
+	return order, nil
+}
+
+// Execute executes the graph and prints a summary.
+// Returns true if all the nodes have been executed successfully,
+// or skipped by conditions.
+func (ex *Executor) Execute(graph gcontract.Graph) bool {
+	ex.Log.UsingExecutionGraph(graph)
+
+	success, summary := ex.execute(graph, ex.State)
+	ex.Summary = summary
+
+	return success
+}
+
+// Teardown executes the teardown graph and prints it's own summary.
+// Returns true if all the teardown nodes have been executed successfully.
+func (ex *Executor) Teardown(graph gcontract.Graph) bool {
+	ex.Log.TearingDown()
+
+	success, _ := ex.execute(graph, nil)
+	return success
+}
+
+// CleanUp executes the cleanup graph and prints it's own summary.
+// Returns true if all the created resources have been deleted.
+func (ex *Executor) CleanUp(graph gcontract.Graph) bool {
+	ex.Log.CleaningUp()
+
+	success, _ := ex.execute(graph, nil)
+	return success
+}
+
+func (ex *Executor) execute(graph gcontract.Graph, state *RunState) (bool, []contract.ScriptNodeSummary) {
+	order, err := ExecutionOrder(graph)
+	if err != nil {
+		ex.Log.Error(err)
+		return false, nil
+	}
+
+	if ex.Sequential {
+		for _, n := range order {
+			ex.Run(graph, n)
+			close(n.Done)
+			ex.record(state, n)
+		}
+	} else {
+		// Printing the nodes output in order as they get done.
+		printed := make(chan struct{})
+		go func() {
+			for _, n := range order {
+				<-n.Done
+
+				if ex.Output != nil {
+					n.Output.FlushTo(ex.Output)
+				} else {
+					n.Output.Flush()
+				}
+
+				ex.record(state, n)
+			}
+
+			close(printed)
+		}()
+
+		ex.Schedule(graph, order)
+		<-printed
+	}
+
+	success := true
+	summary := []contract.ScriptNodeSummary{}
+
+	for _, n := range order {
+		s := contract.ScriptNodeSummary{
+			Node:      string(n.ID()),
+			Operation: n.Operation.ID(),
+			Status:    contract.NodePassed,
+			Duration:  n.Duration,
+			Items:     len(n.Items),
+		}
+
+		for _, in := range n.Items {
+			if in.Result != nil && in.Result.Success {
+				s.ItemsPassed++
+			}
+		}
+
+		if n.Restored {
+			s.Status = contract.NodeRestored
+		} else if n.Skipped {
+			s.Status = contract.NodeSkipped
+			success = success && n.Conditional
+		} else if n.Result == nil || !n.Result.Success {
+			s.Status = contract.NodeFailed
 			success = false
 		}
+
+		summary = append(summary, s)
+	}
+
+	ex.Log.PrintSummary(summary)
+
+	return success, summary
+}
+
+// record records the node outcome in the state, and saves it.
+func (ex *Executor) record(state *RunState, n *ExecutionNode) {
+	if state == nil {
+		return
 	}
 
-	if !success {
-		// os.Exit(255)
+	state.Record(n)
+
+	if err := state.Save(); err != nil {
+		ex.Log.Error(err)
 	}
 }
 
-// Walk walks the execution graph and executes operations.
-func (ex Executor) Walk(
-	graph gcontract.Graph,
-	n *ExecutionNode,
-	nwg *sync.WaitGroup,
-	nresults *contract.OperationResults,
-) {
-
-	// ex.Log.NOMESSAGE("Walking %s", n.ID())
-	// Executing child nodes first (post-order).
-	anwg := sync.WaitGroup{}
-	anwg.Add(int(graph.AdjacentNodes(n.ID()).Count()))
-	anresults := contract.OperationResults{}
-
-	// TODO: consider moving execution of adjacent nodes
-	// into the Reference.Value() function in truly lazy fashion.
+// Schedule runs the nodes on a pool of workers. A node is queued
+// once all of it's dependencies are done, so every node runs exactly once.
+// Nodes which become ready at the same time are queued in the execution order.
+// Dataset rows are executed in a window of ex.Rows rows, the nodes
+// of the rows past the window are held until the earlier rows are done.
+func (ex *Executor) Schedule(graph gcontract.Graph, order []*ExecutionNode) {
+	workers := ex.Workers
+	if workers <= 0 || workers > len(order) {
+		workers = len(order)
+	}
+
+	pending := map[*ExecutionNode]int{}
+	dependents := map[gcontract.NodeID][]*ExecutionNode{}
+
+	queue := make(chan *ExecutionNode, len(order))
+	done := make(chan *ExecutionNode)
+
+	rowNodes := map[int]int{}
+	firstRow, lastRow := 0, 0
+	held := []*ExecutionNode{}
+
+	inWindow := func(n *ExecutionNode) bool {
+		return ex.Rows <= 0 || n.Row < firstRow+ex.Rows
+	}
+
+	enqueue := func(n *ExecutionNode) {
+		if inWindow(n) {
+			queue <- n
+		} else {
+			held = append(held, n)
+		}
+	}
+
+	for i, n := range order {
+		rowNodes[n.Row]++
+
+		if i == 0 || n.Row < firstRow {
+			firstRow = n.Row
+		}
+
+		if n.Row > lastRow {
+			lastRow = n.Row
+		}
+	}
+
+	for _, n := range order {
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			pending[n]++
+			dependents[an.ID()] = append(dependents[an.ID()], n)
+		}
+
+		if pending[n] == 0 {
+			enqueue(n)
+		}
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for n := range queue {
+				ex.Run(graph, n)
+				done <- n
+			}
+		}()
+	}
+
+	for finished := 0; finished < len(order); finished++ {
+		n := <-done
+		close(n.Done)
+
+		for _, dn := range dependents[n.ID()] {
+			pending[dn]--
+			if pending[dn] == 0 {
+				enqueue(dn)
+			}
+		}
+
+		rowNodes[n.Row]--
+		if n.Row == firstRow && rowNodes[n.Row] == 0 {
+			for firstRow <= lastRow && rowNodes[firstRow] == 0 {
+				firstRow++
+			}
+
+			stillHeld := []*ExecutionNode{}
+			for _, hn := range held {
+				if inWindow(hn) {
+					queue <- hn
+				} else {
+					stillHeld = append(stillHeld, hn)
+				}
+			}
+
+			held = stillHeld
+		}
+	}
+
+	close(queue)
+}
+
+// Run executes a node, whose dependencies must be done by then.
+// Nodes restored from a run state are not executed again.
+// Nodes with failed dependencies, or ones skipped by failures, are skipped.
+// Nodes with dependencies skipped by conditions are skipped too, unless
+// their OnSkipped is OnSkippedRun. Nodes with false conditions are skipped.
+func (ex *Executor) Run(graph gcontract.Graph, n *ExecutionNode) {
+	failed := []string{}
+	skipped := []string{}
+
 	for _an := range graph.AdjacentNodes(n.ID()).Range() {
-		// ex.Log.NOMESSAGE("Child node %s of %s", _an.ID(), n.ID())
 		an := _an.(*ExecutionNode)
-		go ex.Walk(graph, an, &anwg, &anresults)
+
+		if an.Skipped && an.Conditional {
+			skipped = append(skipped, string(an.ID()))
+		} else if an.Skipped || an.Result == nil || !an.Result.Success {
+			failed = append(failed, string(an.ID()))
+		}
+	}
+
+	logger := n.Operation.GetLogger()
+
+	// In sequential mode the output is printed right away.
+	if !ex.Sequential {
+		prevOutput := logger.SetOutput(n.Output)
+		defer logger.SetOutput(prevOutput)
+	}
+
+	if n.Restored {
+		logger.NodeRestored(string(n.ID()))
+		return
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		n.Skipped = true
+		logger.NodeSkipped(string(n.ID()), "the "+strings.Join(failed, ", ")+" dependency has not succeeded")
+		return
+	}
+
+	if len(skipped) > 0 && n.OnSkipped != OnSkippedRun {
+		sort.Strings(skipped)
+		n.Skipped = true
+		n.Conditional = true
+		logger.NodeSkipped(string(n.ID()), "the "+strings.Join(skipped, ", ")+" dependency has been skipped")
+		return
+	}
+
+	missing := []string{}
+	for _, rn := range n.Requires {
+		if rn.Result == nil || (rn.Result.HTTPResponse == nil && rn.Result.Size() == 0) {
+			missing = append(missing, string(rn.ID()))
+		}
 	}
 
-	anwg.Wait()
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		n.Skipped = true
+		n.Conditional = true
+		logger.NodeSkipped(string(n.ID()), "the "+strings.Join(missing, ", ")+" result is not available")
+		return
+	}
 
-	//TODO: check for successful outcome of the previous ops.
+	if n.If != nil {
+		ok, err := n.If.Eval(n.IfContext)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
 
-	n.Lock()
+		if !ok {
+			n.Skipped = true
+			n.Conditional = true
+			logger.NodeSkipped(string(n.ID()), "the condition "+n.If.Source+" is false")
+			return
+		}
+	}
 
-	if n.Result == nil {
-		logger := n.Operation.GetLogger()
-		logger.Buffer(true)
+	start := time.Now()
 
-		// Setting the request enrichment.
-		n.Operation.Data().Reload()
-		n.Operation.Data().Load(&n.Data)
-		n.Operation.Data().URL.Load(n.Operation.Resolve().Host(""))
+	if n.Assert != nil {
+		n.Result = ex.ExecuteAssertions(n, logger)
+	} else if n.ForEach != nil {
+		n.Result = ex.ExecuteForEach(graph, n, logger)
+	} else {
+		n.Result = ex.ExecuteNode(graph, n, logger)
+	}
+
+	n.Duration = time.Since(start)
+}
 
-		opSecurity := n.Operation.Resolve().Security("")
-		// ex.Log.NOMESSAGE("security.GetName() = %s", opSecurity.GetName())
+// ExecuteForEach executes the node once per item of it's forEach array,
+// one by one, and aggregates the item results into the node's own one.
+func (ex *Executor) ExecuteForEach(graph gcontract.Graph, n *ExecutionNode, logger contract.Logger) *contract.OperationResult {
+	result := n.Operation.Result()
 
-		if scriptSec := ex.Script.GetSecurity(opSecurity.GetName()); scriptSec != nil {
-			opSecurity.SetValue(scriptSec.Value)
-			opSecurity.SetToken(scriptSec.Token)
-			opSecurity.SetUsername(scriptSec.Username)
-			opSecurity.SetPassword(scriptSec.Password)
+	items, err := n.ForEach.Items(logger)
+	if err != nil {
+		logger.Error(err)
+		result.Success = false
+		return result
+	}
+
+	for i, item := range items {
+		in, err := n.ForEach.Node(graph.(*ExecutionGraph), n, i, item)
+		if err != nil {
+			logger.Error(err)
+			in = &ExecutionNode{OpRefID: fmt.Sprintf("%s[%d]", n.ID(), i)}
+		} else {
+			in.Result = ex.ExecuteNode(graph, in, logger)
 		}
 
-		enrichment := []contract.RequestEnrichment{
-			n.Operation.Data().Query,
-			n.Operation.Data().Headers,
-			n.Operation.Data().Body,
+		n.Items = append(n.Items, in)
+	}
+
+	return n.ForEach.Aggregate(result, n.Items)
+}
+
+// ExecuteAssertions evaluates the assertions of the node, which makes no request.
+// The node succeeds when all of them are true.
+func (ex *Executor) ExecuteAssertions(n *ExecutionNode, logger contract.Logger) *contract.OperationResult {
+	logger.ExecutingNode(string(n.ID()))
+	logger.TestingOperation(n.Operation)
+
+	result := n.Operation.Result()
+	result.Success = true
 
-			opSecurity,
+	for _, a := range n.Assert {
+		ok, err := a.Eval(n.AssertContext)
+		if err != nil {
+			logger.Error(err)
+			result.Success = false
+		} else if !ok {
+			logger.AssertionFailed(a.Source, a.Explain(n.AssertContext))
+			result.Success = false
 		}
+	}
+
+	if result.Success {
+		logger.OperationOK()
+	} else {
+		logger.OperationFail()
+	}
+
+	return result
+}
 
-		logger.TestingOperation(n.Operation)
+// ExecuteNode sets up the node operation request & response validation,
+// and executes it.
+func (ex *Executor) ExecuteNode(graph gcontract.Graph, n *ExecutionNode, logger contract.Logger) *contract.OperationResult {
+	// Setting the request enrichment.
+	n.Operation.Data().Reload()
+	n.Operation.Data().Load(&n.Data)
+	n.Operation.Data().URL.Load(n.Operation.Resolve().Host(""))
+
+	if n.Host != "" {
+		host := params.NewMemorySource("script hosts")
+		host.Add(params.KeyHost, n.Host)
+		n.Operation.Data().URL.Load(host)
+	}
 
-		// Setting the response validation.
-		v := n.Operation.Resolve().Response(n.Expect.Status, "")
-		// v.SetLogger(logger)
-		v.Expect(expect.JSONBody(n.ExpectBody, graph, logger))
+	opSecurity := n.Operation.Resolve().Security(n.Use.Security)
 
-		n.Result = test.Operation(n.Operation, &enrichment, v, logger)
-		(*nresults)[string(n.ID())] = n.Result
+	if scriptSec := n.Sec[opSecurity.GetName()]; scriptSec != nil {
+		opSecurity.SetValue(scriptSec.Value)
+		opSecurity.SetToken(scriptSec.Token)
+		opSecurity.SetUsername(scriptSec.Username)
+		opSecurity.SetPassword(scriptSec.Password)
+	}
+
+	enrichment := []contract.RequestEnrichment{
+		n.Operation.Data().Query,
+		n.Operation.Data().Headers,
+		n.Operation.Data().Body,
+
+		opSecurity,
+	}
+
+	logger.ExecutingNode(string(n.ID()))
+	logger.TestingOperation(n.Operation)
+
+	if ex.Drift != nil {
+		n.Operation.Resolve().Strict(ex.Drift)
+	}
+
+	// Setting the response validation.
+	v := n.Operation.Resolve().Response(n.Expect.Status, n.Expect.CT)
+	v.Expect(expect.JSONBody(n.ExpectBody, graph, logger))
+
+	if n.Expect.MinSize > 0 || n.Expect.MaxSize > 0 {
+		v.Expect(expect.ContentSize(n.Expect.MinSize, n.Expect.MaxSize, logger))
+	}
+
+	if n.Expect.SHA256 != "" {
+		v.Expect(expect.ContentChecksum("sha256", n.Expect.SHA256, logger))
+	}
 
-		logger.Flush()
-		logger.Buffer(false)
+	if n.Expect.MD5 != "" {
+		v.Expect(expect.ContentChecksum("md5", n.Expect.MD5, logger))
 	}
 
-	n.Unlock()
-	nwg.Done()
+	return test.Operation(n.Operation, &enrichment, v, logger)
 }
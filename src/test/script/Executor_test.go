@@ -0,0 +1,152 @@
+package script
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func orderGraph(edges [][2]string, nodes ...string) *ExecutionGraph {
+	graph := NewExecutionGraph(log.NewPlain(0))
+
+	for _, n := range nodes {
+		graph.AddNode(&ExecutionNode{OpRefID: n})
+	}
+
+	for _, e := range edges {
+		graph.AddEdge(gcontract.NodeID(e[0]), gcontract.NodeID(e[1]))
+	}
+
+	return graph
+}
+
+func orderIDs(order []*ExecutionNode) []string {
+	ids := []string{}
+	for _, n := range order {
+		ids = append(ids, n.OpRefID)
+	}
+
+	return ids
+}
+
+func Test_ExecutionOrder(T *testing.T) {
+	T.Run("Dependencies first", func(T *testing.T) {
+		graph := orderGraph([][2]string{
+			{"createPet", "login"},
+			{"getPet", "createPet"},
+			{"listPets", "login"},
+		}, "getPet", "listPets", "createPet", "login")
+
+		for i := 0; i < 10; i++ {
+			order, err := ExecutionOrder(graph)
+			assert.Nil(T, err)
+			assert.Equal(T, []string{"login", "createPet", "getPet", "listPets"}, orderIDs(order))
+		}
+	})
+
+	T.Run("Dataset rows", func(T *testing.T) {
+		graph := NewExecutionGraph(log.NewPlain(0))
+
+		for _, row := range []int{10, 2, 1} {
+			for _, n := range []string{"login", "getUser"} {
+				graph.AddNode(&ExecutionNode{OpRefID: fmt.Sprintf("row%d.%s", row, n), Row: row})
+			}
+
+			graph.AddEdge(gcontract.NodeID(fmt.Sprintf("row%d.getUser", row)), gcontract.NodeID(fmt.Sprintf("row%d.login", row)))
+		}
+
+		order, err := ExecutionOrder(graph)
+		assert.Nil(T, err)
+		assert.Equal(T, []string{
+			"row1.login", "row1.getUser",
+			"row2.login", "row2.getUser",
+			"row10.login", "row10.getUser",
+		}, orderIDs(order))
+	})
+
+	T.Run("Cycles", func(T *testing.T) {
+		graph := orderGraph([][2]string{
+			{"a", "b"},
+			{"b", "a"},
+		}, "a", "b", "c")
+
+		order, err := ExecutionOrder(graph)
+		assert.Nil(T, order)
+		assert.Contains(T, err.Error(), "a, b")
+	})
+}
+
+func Test_Seed(T *testing.T) {
+	paths := map[string]bool{}
+	mutex := sync.Mutex{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		paths[r.URL.Path] = true
+		mutex.Unlock()
+
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	source := `
+specs:
+  service: ../../../spec/test/oas3.yaml
+hosts:
+  service: ` + server.URL + `
+seed: 42
+operations:
+  getAlice:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: "{{randomString(12)}}"
+    expect:
+      status: 400
+  getBob:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: "{{randomString(12)}}"
+    expect:
+      status: 400
+`
+
+	execute := func(only ...string) map[string]bool {
+		mutex.Lock()
+		paths = map[string]bool{}
+		mutex.Unlock()
+
+		s := loadTestScriptSource(T, source, "")
+		graph := s.GetExecutionGraph()
+		if len(only) > 0 {
+			graph = SelectOnly(graph, only)
+		}
+
+		ex := NewExecutor(log.NewPlain(0), s)
+		ex.Workers = 2
+		ex.Execute(graph)
+
+		return paths
+	}
+
+	all := execute()
+	assert.Len(T, all, 2)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(T, all, execute())
+	}
+
+	bob := execute("getBob")
+	assert.Len(T, bob, 1)
+
+	for path := range bob {
+		assert.True(T, all[path])
+	}
+}
@@ -0,0 +1,167 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+// ForEach expands a script node into one execution per element
+// of a referenced response array, like "#listPets.response[*].id".
+// The part of the selector before [*] selects the array, the part after it
+// selects a value from every element. Without [*] the whole selected value
+// is the array.
+type ForEach struct {
+	Script       *Script
+	OpRef        *OperationRef
+	OpRefID      string
+	OpID         string
+	Source       *contract.OperationResult
+	Selector     string
+	ItemSelector string
+	Limit        int
+}
+
+// SetupForEach adds an edge between the node & the operation referenced
+// in opRef.ForEach, and makes the node a forEach one.
+func (script *Script) SetupForEach(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	isref, op2RefID, selector := Dereference(opRef.ForEach)
+	if !isref {
+		return errors.Oops(fmt.Sprintf("The forEach of '%s' must be a reference like #listPets.response[*].id, got '%s'.", opRefID, opRef.ForEach), nil)
+	}
+
+	op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, DependencyForEach)
+	if err != nil {
+		return err
+	}
+
+	fe := &ForEach{
+		Script:   script,
+		OpRef:    opRef,
+		OpRefID:  opRefID,
+		OpID:     op2.ID(),
+		Source:   op2.Result(),
+		Selector: selector,
+		Limit:    opRef.Limit,
+	}
+
+	if i := strings.Index(selector, "[*]"); i >= 0 {
+		fe.Selector, fe.ItemSelector = selector[:i], selector[i+3:]
+	}
+
+	opNode.ForEach = fe
+
+	return nil
+}
+
+// Items returns the elements of the referenced array, no more than fe.Limit of them.
+func (fe *ForEach) Items(log contract.Logger) ([]interface{}, error) {
+	data, err := test.Document(fe.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	access, _ := params.ParseSelector(fe.Selector, log)
+	arr, ok := access(data, log).([]interface{})
+	if !ok {
+		return nil, errors.Oops(fmt.Sprintf("The forEach of '%s' references #%s.response%s, which is not an array.", fe.OpRefID, fe.OpID, fe.Selector), nil)
+	}
+
+	if fe.Limit > 0 && len(arr) > fe.Limit {
+		arr = arr[:fe.Limit]
+	}
+
+	itemAccess, _ := params.ParseSelector(fe.ItemSelector, log)
+
+	items := []interface{}{}
+	for _, v := range arr {
+		items = append(items, itemAccess(v, log))
+	}
+
+	return items, nil
+}
+
+// Node creates a node to execute with a single item. It has the item
+// & it's index interpolated into the data, and is not added to the graph,
+// sharing the dependencies & security of the forEach node n.
+func (fe *ForEach) Node(graph *ExecutionGraph, n *ExecutionNode, index int, item interface{}) (*ExecutionNode, error) {
+	opRef, err := fe.OpRef.Interpolate(ItemVars(index, item))
+	if err != nil {
+		return nil, err
+	}
+
+	itemNode := NewExecutionNode(fe.Script.NewOperation(opRef.OperationID), fmt.Sprintf("%s[%d]", n.ID(), index), opRef, fe.Script.Log)
+	itemNode.Spec = n.Spec
+	itemNode.Host = n.Host
+	itemNode.Row = n.Row
+	itemNode.Sec = n.Sec
+
+	err = fe.Script.SetupData(graph, opRef, itemNode, fe.OpRefID)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemNode, nil
+}
+
+// Aggregate fills the forEach node's own result with the item results,
+// so dependents can reference their response bodies as an array.
+// The result is successful when all the items have succeeded.
+func (fe *ForEach) Aggregate(result *contract.OperationResult, items []*ExecutionNode) *contract.OperationResult {
+	docs := []interface{}{}
+	result.Success = true
+
+	for _, in := range items {
+		if in.Result == nil || !in.Result.Success {
+			result.Success = false
+		}
+
+		var doc interface{}
+		if in.Result != nil && in.Result.Size() > 0 {
+			doc, _ = test.Document(in.Result)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	result.ResponseBytes, _ = json.Marshal(docs)
+
+	return result
+}
+
+// ItemVars returns the forEach item & it's index as script variables.
+// Object fields are available as ${item.field}.
+func ItemVars(index int, item interface{}) Vars {
+	vars := Vars{
+		ItemVar:  itemValue(item),
+		IndexVar: strconv.Itoa(index),
+	}
+
+	if obj, ok := item.(map[string]interface{}); ok {
+		for k, v := range obj {
+			vars[ItemVar+"."+k] = itemValue(v)
+		}
+	}
+
+	return vars
+}
+
+// itemValue casts scalar values to strings, and encodes objects & arrays as JSON.
+func itemValue(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	return params.Cast(v)
+}
@@ -0,0 +1,126 @@
+package script
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+func Test_ForEach(T *testing.T) {
+	source := &contract.OperationResult{
+		Success:       true,
+		ResponseBytes: []byte(`{"pets": [{"id": 1, "name": "Rex"}, {"id": 2, "name": "Tom"}, {"id": 3, "name": "Jerry"}]}`),
+	}
+
+	T.Run("Items", func(T *testing.T) {
+		fe := &ForEach{Source: source, Selector: ".pets", ItemSelector: ".id"}
+
+		items, err := fe.Items(log.NewPlain(0))
+		assert.Nil(T, err)
+		assert.Equal(T, []interface{}{float64(1), float64(2), float64(3)}, items)
+	})
+
+	T.Run("Limit", func(T *testing.T) {
+		fe := &ForEach{Source: source, Selector: ".pets", ItemSelector: ".name", Limit: 2}
+
+		items, err := fe.Items(log.NewPlain(0))
+		assert.Nil(T, err)
+		assert.Equal(T, []interface{}{"Rex", "Tom"}, items)
+	})
+
+	T.Run("Not an array", func(T *testing.T) {
+		fe := &ForEach{Source: source, OpID: "listPets"}
+
+		_, err := fe.Items(log.NewPlain(0))
+		assert.NotNil(T, err)
+	})
+
+	T.Run("ItemVars", func(T *testing.T) {
+		vars := ItemVars(1, map[string]interface{}{"id": float64(2), "tags": []interface{}{"cat"}})
+
+		assert.Equal(T, Vars{
+			"item":      `{"id":2,"tags":["cat"]}`,
+			"item.id":   "2",
+			"item.tags": `["cat"]`,
+			"index":     "1",
+		}, vars)
+	})
+
+	T.Run("Interpolate", func(T *testing.T) {
+		opRef := &OperationRef{
+			OperationID: "service.getPetById",
+			Use: OperationDataUse{
+				Path: OperationDataMap{"petId": "${item}"},
+			},
+			Expect: OperationDataExpect{
+				Body: OperationDataMap{"index": "${index}"},
+			},
+		}
+
+		itemRef, err := opRef.Interpolate(ItemVars(0, "42"))
+		assert.Nil(T, err)
+		assert.Equal(T, "42", itemRef.Use.Path["petId"])
+		assert.Equal(T, "0", itemRef.Expect.Body["index"])
+		assert.Equal(T, "${item}", opRef.Use.Path["petId"])
+
+		_, err = opRef.Interpolate(Vars{})
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Untrusted items", func(T *testing.T) {
+		os.Setenv("OASIS_TEST_SECRET", "s3cr3t")
+		defer os.Unsetenv("OASIS_TEST_SECRET")
+
+		opRef := &OperationRef{
+			OperationID: "service.getPetById",
+			Use: OperationDataUse{
+				Path:  OperationDataMap{"petId": "${item.id}"},
+				Query: OperationDataMap{"name": "${item.name}", "tpl": "${item.tpl}"},
+			},
+		}
+
+		itemRef, err := opRef.Interpolate(ItemVars(0, map[string]interface{}{
+			"id":   "#login.response.token",
+			"name": "${env:OASIS_TEST_SECRET}",
+			"tpl":  "{{uuid()}}",
+		}))
+
+		assert.Nil(T, err)
+		assert.Equal(T, "${env:OASIS_TEST_SECRET}", itemRef.Use.Query["name"])
+		assert.True(T, itemRef.IsLiteral("path", "petId"))
+		assert.False(T, opRef.IsLiteral("path", "petId"))
+
+		script := &Script{EntityTrait: contract.Entity(log.NewPlain(0))}
+		node := NewExecutionNode(nil, "getPet[0]", itemRef, log.NewPlain(0))
+		assert.Nil(T, script.SetupData(NewExecutionGraph(log.NewPlain(0)), itemRef, node, "getPet"))
+
+		values := map[string]string{}
+		for _, set := range []contract.Set{node.Data.URL, node.Data.Query} {
+			for p := range set.Iterate() {
+				values[p.N] = p.V()
+			}
+		}
+
+		assert.Equal(T, "#login.response.token", values["petId"])
+		assert.Equal(T, "${env:OASIS_TEST_SECRET}", values["name"])
+		assert.Equal(T, "{{uuid()}}", values["tpl"])
+	})
+
+	T.Run("Aggregate", func(T *testing.T) {
+		items := []*ExecutionNode{
+			{Result: &contract.OperationResult{Success: true, ResponseBytes: []byte(`{"id": 1}`)}},
+			{Result: &contract.OperationResult{Success: false}},
+		}
+
+		result := (&ForEach{}).Aggregate(&contract.OperationResult{}, items)
+		assert.False(T, result.Success)
+
+		doc, err := test.Document(result)
+		assert.Nil(T, err)
+		assert.Equal(T, []interface{}{map[string]interface{}{"id": float64(1)}, nil}, doc)
+	})
+}
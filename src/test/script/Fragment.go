@@ -0,0 +1,363 @@
+package script
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+)
+
+// InputVar is the namespace of the fragment inputs, like ${input.pin}.
+const InputVar = "input"
+
+// FragmentDepthLimit limits the nesting of fragments instantiating other fragments.
+const FragmentDepthLimit = 16
+
+var (
+	fragmentRefRx    = regexp.MustCompile(`#(\w+(?:\.\w+)*?)\.response`)
+	fragmentOutputRx = regexp.MustCompile(`#\w+(?:\.\w+)*`)
+)
+
+// ScriptFragment corresponds to an item of the 'fragments' block of a script file.
+// It's a named set of operations, instantiated in the script operations
+// with the `fragment` field, and arguments for it's inputs in the `with` block:
+//
+//	login:
+//	  fragment: login
+//	  with:
+//	    pin: 111
+//
+// The fragment operations get the instance name prefixed to their node IDs,
+// like "login.pinLogin", and take the arguments as ${input.pin}.
+// Outputs are references to the fragment operations' responses,
+// available in the script as "#login.token".
+type ScriptFragment struct {
+	Inputs     []string               `yaml:"inputs"`
+	Outputs    map[string]string      `yaml:"outputs"`
+	Operations map[string]interface{} `yaml:"operations"`
+}
+
+// LoadIncludes merges the script files from the 'include' block into
+// the script file data. The script's own values take precedence over the included
+// ones, earlier includes take precedence over the later ones. Blocks like
+// 'operations' or 'fragments' are merged by their keys.
+// Relative include paths are relative to the including file.
+func LoadIncludes(path string, fileData []byte, including []string) ([]byte, error) {
+	including = append(append([]string{}, including...), absPath(path))
+
+	data := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(fileData, &data); err != nil {
+		return nil, errors.Oops("Failed to parse the script '"+path+"'.", err)
+	}
+
+	includes := []interface{}{}
+	switch v := data["include"].(type) {
+	case string:
+		includes = append(includes, v)
+
+	case []interface{}:
+		includes = v
+
+	case nil:
+		return fileData, nil
+	}
+
+	delete(data, "include")
+
+	for _, inc := range includes {
+		incPath := fmt.Sprint(inc)
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(path), incPath)
+		}
+
+		for _, p := range including {
+			if p == absPath(incPath) {
+				return nil, errors.Oops("The script '"+path+"' includes '"+incPath+"', which includes it back.", nil)
+			}
+		}
+
+		incFileData, err := ioutil.ReadFile(incPath)
+		if err != nil {
+			return nil, errors.Oops("Failed to read the script '"+incPath+"' included in '"+path+"'.", err)
+		}
+
+		incFileData, err = LoadIncludes(incPath, incFileData, including)
+		if err != nil {
+			return nil, err
+		}
+
+		incData := map[interface{}]interface{}{}
+		if err := yaml.Unmarshal(incFileData, &incData); err != nil {
+			return nil, errors.Oops("Failed to parse the script '"+incPath+"'.", err)
+		}
+
+		for k, v := range incData {
+			own, ok := data[k]
+			if !ok {
+				data[k] = v
+				continue
+			}
+
+			ownBlock, ok1 := own.(map[interface{}]interface{})
+			incBlock, ok2 := v.(map[interface{}]interface{})
+			if ok1 && ok2 {
+				for bk, bv := range incBlock {
+					if _, ok := ownBlock[bk]; !ok {
+						ownBlock[bk] = bv
+					}
+				}
+			}
+		}
+	}
+
+	return yaml.Marshal(data)
+}
+
+// ExpandFragments replaces the fragment instances in the operations & teardown
+// blocks of the script file data with the fragment operations, and the
+// instance output references with the operation references.
+func ExpandFragments(data map[interface{}]interface{}) error {
+	fragments := map[string]*ScriptFragment{}
+
+	if raw, ok := data["fragments"]; ok {
+		fragmentsData, err := yaml.Marshal(raw)
+		if err != nil {
+			return errors.Oops("Failed to parse the script fragments.", err)
+		}
+
+		if err := yaml.Unmarshal(fragmentsData, &fragments); err != nil {
+			return errors.Oops("Failed to parse the script fragments.", err)
+		}
+
+		delete(data, "fragments")
+	}
+
+	outputs := map[string]string{}
+
+	for _, block := range []string{"operations", "teardown"} {
+		ops, ok := data[block].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		expanded, blockOutputs, err := expandOperations(ops, fragments, 0)
+		if err != nil {
+			return err
+		}
+
+		data[block] = expanded
+
+		for k, v := range blockOutputs {
+			outputs[k] = v
+		}
+	}
+
+	replaceOutputs(data, outputs)
+
+	return nil
+}
+
+// expandOperations returns ops with the fragment instances replaced
+// by the fragment operations, along with the instance outputs.
+func expandOperations(ops map[interface{}]interface{}, fragments map[string]*ScriptFragment, depth int) (map[interface{}]interface{}, map[string]string, error) {
+	if depth > FragmentDepthLimit {
+		return nil, nil, errors.Oops("The script fragments are nested too deep, check them for cycles.", nil)
+	}
+
+	res := map[interface{}]interface{}{}
+	outputs := map[string]string{}
+
+	for k, op := range ops {
+		name := fmt.Sprint(k)
+
+		instOps := map[interface{}]interface{}{k: op}
+
+		if opData, ok := op.(map[interface{}]interface{}); ok && opData["fragment"] != nil {
+			var instOutputs map[string]string
+			var err error
+
+			instOps, instOutputs, err = instantiateFragment(name, opData, fragments, depth)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for out, ref := range instOutputs {
+				outputs[out] = ref
+			}
+		}
+
+		for ik, iv := range instOps {
+			if _, ok := res[ik]; ok {
+				return nil, nil, errors.Oops("The fragment operation '"+fmt.Sprint(ik)+"' has the same name as a script operation.", nil)
+			}
+
+			res[ik] = iv
+		}
+	}
+
+	return res, outputs, nil
+}
+
+// instantiateFragment creates the operations of a fragment instance.
+func instantiateFragment(name string, inst map[interface{}]interface{}, fragments map[string]*ScriptFragment, depth int) (map[interface{}]interface{}, map[string]string, error) {
+	instErr := func(msg string, err error) error {
+		return errors.Oops("The operation '"+name+"' "+msg, err)
+	}
+
+	fragName := fmt.Sprint(inst["fragment"])
+
+	frag := fragments[fragName]
+	if frag == nil {
+		return nil, nil, instErr("references an unknown fragment '"+fragName+"'.", nil)
+	}
+
+	args, _ := inst["with"].(map[interface{}]interface{})
+
+	vars := Vars{}
+	for _, in := range frag.Inputs {
+		arg, ok := args[in]
+		if !ok {
+			return nil, nil, instErr("doesn't set the '"+in+"' input of the fragment '"+fragName+"'.", nil)
+		}
+
+		vars[InputVar+"."+in] = params.Cast(arg)
+	}
+
+	for k := range args {
+		if _, ok := vars[InputVar+"."+fmt.Sprint(k)]; !ok {
+			return nil, nil, instErr("sets the '"+fmt.Sprint(k)+"' input, which the fragment '"+fragName+"' doesn't declare.", nil)
+		}
+	}
+
+	// Copying the fragment operations, so every instance has it's own.
+	opsData, err := yaml.Marshal(frag.Operations)
+	if err != nil {
+		return nil, nil, instErr("failed to instantiate the fragment '"+fragName+"'.", err)
+	}
+
+	ops := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(opsData, &ops); err != nil {
+		return nil, nil, instErr("failed to instantiate the fragment '"+fragName+"'.", err)
+	}
+
+	if _, err := vars.InterpolateData(ops, ItemVar, IndexVar, RowVar); err != nil {
+		return nil, nil, instErr("failed to instantiate the fragment '"+fragName+"'.", err)
+	}
+
+	ops, nestedOutputs, err := expandOperations(ops, fragments, depth+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replaceOutputs(ops, nestedOutputs)
+
+	names := map[string]bool{}
+	for k := range ops {
+		names[fmt.Sprint(k)] = true
+	}
+
+	namespace := func(s string) string {
+		return fragmentRefRx.ReplaceAllStringFunc(s, func(ref string) string {
+			if opRefID := fragmentRefRx.FindStringSubmatch(ref)[1]; names[opRefID] {
+				return "#" + name + "." + opRefID + ".response"
+			}
+
+			return ref
+		})
+	}
+
+	after, _ := inst["after"].(string)
+	res := map[interface{}]interface{}{}
+
+	for k, op := range ops {
+		if opData, ok := op.(map[interface{}]interface{}); ok {
+			mapStrings(opData, namespace)
+
+			if opAfter, ok := opData["after"].(string); ok && names[opAfter] {
+				opData["after"] = name + "." + opAfter
+			} else if !ok && after != "" {
+				opData["after"] = after
+			}
+		}
+
+		res[name+"."+fmt.Sprint(k)] = op
+	}
+
+	outputs := map[string]string{}
+	for out, ref := range frag.Outputs {
+		if ref, err = vars.Interpolate(ref); err != nil {
+			return nil, nil, instErr("failed to instantiate the fragment '"+fragName+"'.", err)
+		}
+
+		outputs[name+"."+out] = namespace(replaceOutput(ref, nestedOutputs))
+	}
+
+	return res, outputs, nil
+}
+
+// absPath returns the cleaned absolute path, or just the cleaned one
+// when it can't be made absolute.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(path)
+}
+
+// replaceOutputs replaces the fragment instance output references
+// in all the string values of data, like "#login.token".
+func replaceOutputs(data interface{}, outputs map[string]string) {
+	if len(outputs) == 0 {
+		return
+	}
+
+	mapStrings(data, func(s string) string {
+		return replaceOutput(s, outputs)
+	})
+}
+
+// replaceOutput replaces the output references in s with the references
+// they stand for, keeping the selectors following them.
+func replaceOutput(s string, outputs map[string]string) string {
+	return fragmentOutputRx.ReplaceAllStringFunc(s, func(ref string) string {
+		path := strings.Split(ref[1:], ".")
+
+		for i := len(path); i > 0; i-- {
+			if out, ok := outputs[strings.Join(path[:i], ".")]; ok {
+				return strings.Join(append([]string{out}, path[i:]...), ".")
+			}
+		}
+
+		return ref
+	})
+}
+
+// mapStrings replaces all the string values of data unmarshalled
+// from YAML with their f results, recursively.
+func mapStrings(data interface{}, f func(string) string) {
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		for k, item := range v {
+			if s, ok := item.(string); ok {
+				v[k] = f(s)
+			} else {
+				mapStrings(item, f)
+			}
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				v[i] = f(s)
+			} else {
+				mapStrings(item, f)
+			}
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 // Dereference checks if v is a reference to another operation
 // and returns a ParameterAccess function for it.
 func Dereference(v string) (bool, string, string) {
-	rx := regexp.MustCompile("#(?P<opRef>\\w+)\\.response(?P<selector>.*)")
+	rx := regexp.MustCompile("^#(?P<opRef>\\w+(?:\\.\\w+)*?)\\.response(?P<selector>.*)$")
 
 	if rx.Match([]byte(v)) {
 		matches := strings.RxMatches(v, rx)
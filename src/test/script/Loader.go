@@ -1,21 +1,29 @@
 package script
 
 import (
+	"fmt"
 	"io/ioutil"
 
 	"github.com/go-yaml/yaml"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
 	"github.com/x1n13y84issmd42/oasis/src/utility"
 )
 
-// Load loads a script file.
-func Load(path string, log contract.Logger) contract.Script {
+// Load loads a script file. The environment selects one of the script
+// environments to take variables & hosts from, it may be empty.
+func Load(path string, environment string, log contract.Logger) contract.Script {
 	fileData, fileErr := ioutil.ReadFile(path)
 	if fileErr != nil {
 		return NoScript(fileErr, log)
 	}
 
+	fileData, err := LoadIncludes(path, fileData, nil)
+	if err != nil {
+		return NoScript(err, log)
+	}
+
 	script := &Script{
 		EntityTrait: contract.Entity(log),
 		Sec:         make(map[string]*contract.SecurityAccess),
@@ -23,17 +31,177 @@ func Load(path string, log contract.Logger) contract.Script {
 
 	yaml.Unmarshal([]byte(fileData), script)
 
+	if err := script.Interpolate(fileData, environment); err != nil {
+		return NoScript(err, log)
+	}
+
+	script.ApplyDefaults()
+
+	if script.Dataset != nil {
+		rows, err := script.Dataset.Load()
+		if err != nil {
+			return NoScript(err, log)
+		}
+
+		for i, row := range rows {
+			it, err := script.Iteration(i+1, row)
+			if err != nil {
+				return NoScript(err, log)
+			}
+
+			script.Iterations = append(script.Iterations, it)
+		}
+	}
+
 	specs := make(map[string]contract.OperationAccess)
 
-	for k, v := range script.SpecPaths {
-		spec := utility.Load(v, script.Log)
+	for k, v := range script.Specs {
+		spec := utility.Load(v.Path, script.Log)
 		specs[k] = spec
 	}
 
 	script.OperationCache = api.NewOperationCache(specs)
 
+	for _, it := range script.Iterations {
+		it.OperationCache = script.OperationCache
+	}
+
 	//TODO: some validation is required
 	// Like unique node IDs.
 
 	return script
 }
+
+// Interpolate selects the environment, and replaces variable references
+// in the script values, except the vars & environments blocks themselves,
+// so unused environments may reference undefined variables.
+// The fragment instances are expanded, see ExpandFragments().
+// The dataset row references are interpolated later, per iteration,
+// and the forEach item references are interpolated at run time.
+func (script *Script) Interpolate(fileData []byte, environment string) error {
+	vars := Vars{}
+	for k, v := range script.Vars {
+		vars[k] = v
+	}
+
+	hosts := map[string]string{}
+	for k, v := range script.Hosts {
+		hosts[k] = v
+	}
+
+	if environment != "" {
+		env := script.Environments[environment]
+		if env == nil {
+			return errors.Oops("The environment '"+environment+"' is not defined in the script.", nil)
+		}
+
+		for k, v := range env.Vars {
+			vars[k] = v
+		}
+
+		for k, v := range env.Hosts {
+			hosts[k] = v
+		}
+	}
+
+	data := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(fileData, &data); err != nil {
+		return errors.Oops("Failed to parse the script.", err)
+	}
+
+	delete(data, "vars")
+	delete(data, "environments")
+
+	deferred := []string{ItemVar, IndexVar}
+	if _, ok := data["dataset"]; ok {
+		deferred = append(deferred, RowVar)
+	}
+
+	if _, err := vars.InterpolateData(data, append(deferred, InputVar)...); err != nil {
+		return err
+	}
+
+	if err := ExpandFragments(data); err != nil {
+		return err
+	}
+
+	// The fragment inputs are not available outside of the fragments.
+	if _, err := (Vars{}).InterpolateData(data, deferred...); err != nil {
+		return err
+	}
+
+	fileData, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Oops("Failed to interpolate the script.", err)
+	}
+
+	// Unmarshalling merges maps, the fragment instances must go.
+	script.Operations = nil
+	script.Teardown = nil
+
+	yaml.Unmarshal(fileData, script)
+	script.source = fileData
+
+	script.Hosts = map[string]string{}
+	for k, v := range hosts {
+		if script.Hosts[k], err = vars.Interpolate(v, deferred...); err != nil {
+			return err
+		}
+	}
+
+	// Unused variables may reference undefined ones, those are kept as is.
+	script.vars = map[string]string{}
+	for k, v := range vars {
+		script.vars[k], _ = vars.Interpolate(v, deferred...)
+	}
+
+	return nil
+}
+
+// Iteration creates a copy of the script to execute with a dataset row,
+// with the row fields interpolated. The copy's node IDs are prefixed
+// with the row number, like "row2.login".
+func (script *Script) Iteration(index int, row DatasetRow) (*Script, error) {
+	rowErr := func(err error) error {
+		return errors.Oops(fmt.Sprintf("Failed to interpolate the dataset row %d.", index), err)
+	}
+
+	data := map[interface{}]interface{}{}
+	if err := yaml.Unmarshal(script.source, &data); err != nil {
+		return nil, rowErr(err)
+	}
+
+	delete(data, "dataset")
+
+	vars := row.Vars()
+
+	if _, err := vars.SubstituteData(data, ItemVar, IndexVar); err != nil {
+		return nil, rowErr(err)
+	}
+
+	fileData, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, rowErr(err)
+	}
+
+	it := &Script{
+		EntityTrait: script.EntityTrait,
+		Sec:         make(map[string]*contract.SecurityAccess),
+		Row:         index,
+	}
+
+	if err := yaml.Unmarshal(fileData, it); err != nil {
+		return nil, rowErr(err)
+	}
+
+	it.Hosts = map[string]string{}
+	for k, v := range script.Hosts {
+		if it.Hosts[k], err = vars.Substitute(v); err != nil {
+			return nil, rowErr(err)
+		}
+	}
+
+	it.ApplyDefaults()
+
+	return it, nil
+}
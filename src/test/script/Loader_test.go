@@ -0,0 +1,272 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+const loaderTestScript = `
+specs:
+  service: ../../../spec/test/oas3.yaml
+vars:
+  USER: gopher
+hosts:
+  service: https://dev.example.com
+environments:
+  staging:
+    vars:
+      USER: rustacean
+    hosts:
+      service: https://staging.example.com/${USER}
+  prod:
+    vars:
+      USER: ${env:OASIS_UNDEFINED_VARIABLE}
+operations:
+  getUser:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: ${USER}
+`
+
+const loaderTestDatasetScript = `
+specs:
+  service: ../../../spec/test/oas3.yaml
+vars:
+  DOMAIN: example.com
+dataset:
+  parallelism: 2
+  rows:
+    - name: gopher
+    - name: rustacean
+operations:
+  getUser:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: ${row.name}@${DOMAIN}
+`
+
+func loadTestScript(T *testing.T, environment string) *Script {
+	return loadTestScriptSource(T, loaderTestScript, environment)
+}
+
+func loadTestScriptSource(T *testing.T, source string, environment string) *Script {
+	file, err := ioutil.TempFile("", "oasis-script-*.yaml")
+	assert.Nil(T, err)
+	defer os.Remove(file.Name())
+
+	file.WriteString(source)
+	file.Close()
+
+	s, _ := Load(file.Name(), environment, log.NewPlain(0)).(*Script)
+	return s
+}
+
+func Test_Load(T *testing.T) {
+	T.Run("Vars", func(T *testing.T) {
+		s := loadTestScript(T, "")
+
+		assert.Equal(T, "gopher", s.Operations["getUser"].Use.Path["username"])
+		assert.Equal(T, "https://dev.example.com", s.Hosts["service"])
+	})
+
+	T.Run("Environment", func(T *testing.T) {
+		s := loadTestScript(T, "staging")
+
+		assert.Equal(T, "rustacean", s.Operations["getUser"].Use.Path["username"])
+		assert.Equal(T, "https://staging.example.com/rustacean", s.Hosts["service"])
+	})
+
+	T.Run("Unknown environment", func(T *testing.T) {
+		assert.Nil(T, loadTestScript(T, "qa"))
+	})
+	T.Run("Dataset", func(T *testing.T) {
+		s := loadTestScriptSource(T, loaderTestDatasetScript, "")
+
+		assert.Equal(T, 2, s.GetRowParallelism())
+		assert.Equal(T, 2, len(s.Iterations))
+		assert.Equal(T, "gopher@example.com", s.Iterations[0].Operations["getUser"].Use.Path["username"])
+		assert.Equal(T, "rustacean@example.com", s.Iterations[1].Operations["getUser"].Use.Path["username"])
+		assert.Equal(T, "row2.getUser", s.Iterations[1].NodeID("getUser"))
+	})
+
+	T.Run("Literal row fields", func(T *testing.T) {
+		os.Setenv("OASIS_TEST_SECRET", "s3cr3t")
+		defer os.Unsetenv("OASIS_TEST_SECRET")
+
+		file := filepath.Join(T.TempDir(), "rows.csv")
+		assert.Nil(T, ioutil.WriteFile(file, []byte("name\n${foo} ${env:OASIS_TEST_SECRET}\n"), 0644))
+
+		source := strings.Replace(loaderTestDatasetScript, "  rows:\n    - name: gopher\n    - name: rustacean\n", "  file: "+file+"\n", 1)
+		s := loadTestScriptSource(T, source, "")
+
+		assert.Equal(T, 1, len(s.Iterations))
+		assert.Equal(T, "${foo} ${env:OASIS_TEST_SECRET}@example.com", s.Iterations[0].Operations["getUser"].Use.Path["username"])
+	})
+
+	T.Run("Undefined row field", func(T *testing.T) {
+		source := strings.Replace(loaderTestDatasetScript, "${row.name}", "${row.email}", 1)
+		assert.Nil(T, loadTestScriptSource(T, source, ""))
+	})
+
+	T.Run("Row field without a dataset", func(T *testing.T) {
+		source := strings.Replace(loaderTestScript, "username: ${USER}", "username: ${row.name}", 1)
+		assert.Nil(T, loadTestScriptSource(T, source, ""))
+	})
+	T.Run("Condition", func(T *testing.T) {
+		source := loaderTestScript + `
+  deleteUser:
+    operationId: service.deleteUser
+    if: "#getUser.response.username == ${USER}"
+    onSkipped: run
+    use:
+      path:
+        username: ${USER}
+`
+		s := loadTestScriptSource(T, source, "")
+		graph := s.GetExecutionGraph()
+
+		n := graph.Node("deleteUser").(*ExecutionNode)
+		assert.Equal(T, "#getUser.response.username == gopher", n.If.Source)
+		assert.Equal(T, OnSkippedRun, n.OnSkipped)
+		assert.NotNil(T, graph.AdjacentNodes("deleteUser").Get("getUser"))
+	})
+	T.Run("Teardown", func(T *testing.T) {
+		source := loaderTestScript + `
+teardown:
+  deleteUser:
+    operationId: service.deleteUser
+    use:
+      path:
+        username: "#getUser.response.username"
+`
+		s := loadTestScriptSource(T, source, "")
+		assert.Nil(T, s.GetTeardownGraph())
+
+		graph := s.GetExecutionGraph()
+		assert.Equal(T, uint(1), graph.Len())
+
+		teardown := s.GetTeardownGraph()
+		assert.Equal(T, uint(1), teardown.Len())
+
+		n := teardown.Node("deleteUser").(*ExecutionNode)
+		assert.Equal(T, []*ExecutionNode{graph.Node("getUser").(*ExecutionNode)}, n.Requires)
+	})
+	T.Run("Fragments", func(T *testing.T) {
+		source := loaderTestScript + `
+  admin:
+    fragment: user
+    with:
+      name: admin
+  deleteUser:
+    operationId: service.deleteUser
+    use:
+      path:
+        username: "#admin.name"
+fragments:
+  user:
+    inputs: [name]
+    outputs:
+      name: "#getUser.response.username"
+    operations:
+      getUser:
+        operationId: service.getUserByName
+        use:
+          path:
+            username: ${input.name}
+      updateUser:
+        operationId: service.updateUser
+        after: getUser
+        use:
+          path:
+            username: "{{#getUser.response.username}}"
+`
+		s := loadTestScriptSource(T, source, "")
+
+		assert.Equal(T, "admin", s.Operations["admin.getUser"].Use.Path["username"])
+		assert.Equal(T, "admin.getUser", s.Operations["admin.updateUser"].After)
+		assert.Equal(T, "{{#admin.getUser.response.username}}", s.Operations["admin.updateUser"].Use.Path["username"])
+		assert.Equal(T, "#admin.getUser.response.username", s.Operations["deleteUser"].Use.Path["username"])
+
+		graph := s.GetExecutionGraph()
+		assert.Equal(T, uint(4), graph.Len())
+		assert.NotNil(T, graph.AdjacentNodes("deleteUser").Get("admin.getUser"))
+		assert.NotNil(T, graph.AdjacentNodes("admin.updateUser").Get("admin.getUser"))
+	})
+
+	T.Run("Fragment inputs", func(T *testing.T) {
+		source := loaderTestScript + `
+  admin:
+    fragment: user
+fragments:
+  user:
+    inputs: [name]
+    operations:
+      getUser:
+        operationId: service.getUserByName
+        use:
+          path:
+            username: ${input.name}
+`
+		assert.Nil(T, loadTestScriptSource(T, source, ""))
+		assert.Nil(T, loadTestScriptSource(T, strings.Replace(source, "fragment: user", "fragment: user\n    with:\n      name: admin\n      email: admin@example.com", 1), ""))
+		assert.Nil(T, loadTestScriptSource(T, strings.Replace(loaderTestScript, "username: ${USER}", "username: ${input.name}", 1), ""))
+	})
+
+	T.Run("Include", func(T *testing.T) {
+		file, err := ioutil.TempFile("", "oasis-include-*.yaml")
+		assert.Nil(T, err)
+		defer os.Remove(file.Name())
+
+		file.WriteString(`
+vars:
+  USER: rustacean
+  ROLE: admin
+operations:
+  getAdmin:
+    operationId: service.getUserByName
+    use:
+      path:
+        username: ${ROLE}
+`)
+		file.Close()
+
+		s := loadTestScriptSource(T, "include: "+file.Name()+loaderTestScript, "")
+
+		assert.Equal(T, "gopher", s.Operations["getUser"].Use.Path["username"])
+		assert.Equal(T, "admin", s.Operations["getAdmin"].Use.Path["username"])
+	})
+
+	T.Run("Relative include", func(T *testing.T) {
+		dir := T.TempDir()
+		assert.Nil(T, os.Mkdir(filepath.Join(dir, "lib"), 0755))
+
+		write := func(name string, source string) {
+			assert.Nil(T, ioutil.WriteFile(filepath.Join(dir, name), []byte(source), 0644))
+		}
+
+		write("main.yaml", "include: ./lib/admin.yaml"+loaderTestScript)
+		write("lib/admin.yaml", `
+operations:
+  getAdmin:
+    operationId: service.getUserByName
+`)
+
+		s, _ := Load(filepath.Join(dir, "main.yaml"), "", log.NewPlain(0)).(*Script)
+		assert.NotNil(T, s)
+		assert.NotNil(T, s.Operations["getAdmin"])
+
+		write("lib/admin.yaml", "include: ../main.yaml")
+
+		_, err := LoadIncludes(filepath.Join(dir, "main.yaml"), []byte("include: lib/admin.yaml"), nil)
+		assert.NotNil(T, err)
+		assert.Contains(T, err.Error(), "includes it back")
+	})
+}
@@ -25,8 +25,55 @@ func (s *NullScript) GetExecutionGraph() gcontract.Graph {
 	return nil
 }
 
+// GetTeardownGraph reports an error.
+func (s *NullScript) GetTeardownGraph() gcontract.Graph {
+	s.Report()
+	return nil
+}
+
+// GetCleanupGraph reports an error.
+func (s *NullScript) GetCleanupGraph() gcontract.Graph {
+	s.Report()
+	return nil
+}
+
+// IsStrict reports an error.
+func (s *NullScript) IsStrict() bool {
+	s.Report()
+	return false
+}
+
+// GetParallelism reports an error.
+func (s *NullScript) GetParallelism() int {
+	s.Report()
+	return 0
+}
+
+// IsSequential reports an error.
+func (s *NullScript) IsSequential() bool {
+	s.Report()
+	return false
+}
+
+// GetRowParallelism reports an error.
+func (s *NullScript) GetRowParallelism() int {
+	s.Report()
+	return 0
+}
+
+// GetVars reports an error.
+func (s *NullScript) GetVars() map[string]string {
+	s.Report()
+	return nil
+}
+
 // GetSecurity reports an error.
 func (s *NullScript) GetSecurity(name string) *contract.SecurityAccess {
 	s.Report()
 	return nil
 }
+
+// SetSeed reports an error.
+func (s *NullScript) SetSeed(seed int64) {
+	s.Report()
+}
@@ -0,0 +1,147 @@
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// The execution plan export formats, see ExportPlan().
+const (
+	PlanDOT     = "dot"
+	PlanMermaid = "mermaid"
+)
+
+// GetPlan describes the execution graph of the named script without executing it:
+// the nodes in the execution order, with their spec operations' methods & paths,
+// and the nodes they depend on, along with the reasons.
+func GetPlan(name string, graph gcontract.Graph) (contract.ScriptPlan, error) {
+	plan := contract.ScriptPlan{Script: name}
+
+	order, err := ExecutionOrder(graph)
+	if err != nil {
+		return plan, err
+	}
+
+	eg, _ := graph.(*ExecutionGraph)
+
+	for _, n := range order {
+		pn := contract.ScriptPlanNode{
+			Node:      string(n.ID()),
+			Operation: n.Operation.ID(),
+			Method:    n.Operation.Method(),
+			Path:      n.Operation.Path(),
+			DependsOn: []contract.ScriptPlanDependency{},
+		}
+
+		if n.ForEach != nil {
+			pn.ForEach = n.ForEach.OpRef.ForEach
+		}
+
+		if n.If != nil {
+			pn.If = n.If.Source
+		}
+
+		for _, a := range n.Assert {
+			pn.Assert = append(pn.Assert, a.Source)
+		}
+
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			dep := contract.ScriptPlanDependency{Node: string(an.ID())}
+			if eg != nil {
+				dep.Reasons = eg.Reasons(n.ID(), an.ID())
+			}
+
+			pn.DependsOn = append(pn.DependsOn, dep)
+		}
+
+		sort.Slice(pn.DependsOn, func(i, j int) bool {
+			return pn.DependsOn[i].Node < pn.DependsOn[j].Node
+		})
+
+		plan.Nodes = append(plan.Nodes, pn)
+	}
+
+	return plan, nil
+}
+
+// ExportPlan renders the plan as a Graphviz DOT or a Mermaid flowchart graph,
+// with edges going from the nodes to the ones depending on them.
+func ExportPlan(plan contract.ScriptPlan, format string) (string, error) {
+	switch format {
+	case PlanDOT:
+		return planDOT(plan), nil
+
+	case PlanMermaid:
+		return planMermaid(plan), nil
+	}
+
+	return "", errors.Oops(fmt.Sprintf("Unknown plan format '%s', use either %s or %s.", format, PlanDOT, PlanMermaid), nil)
+}
+
+// planRequest returns the node's request, or the operation
+// of the nodes which make none, like assertions.
+func planRequest(n contract.ScriptPlanNode) string {
+	if n.Method == "" {
+		return n.Operation
+	}
+
+	return n.Method + " " + n.Path
+}
+
+func planDOT(plan contract.ScriptPlan) string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "digraph %s {\n", quote(plan.Script))
+	fmt.Fprintf(b, "\tnode [shape=box];\n")
+
+	for _, n := range plan.Nodes {
+		fmt.Fprintf(b, "\t%s [label=%s];\n", quote(n.Node), quote(n.Node+"\n"+planRequest(n)))
+	}
+
+	for _, n := range plan.Nodes {
+		for _, d := range n.DependsOn {
+			fmt.Fprintf(b, "\t%s -> %s [label=%s];\n", quote(d.Node), quote(n.Node), quote(strings.Join(d.Reasons, ", ")))
+		}
+	}
+
+	fmt.Fprintf(b, "}\n")
+
+	return b.String()
+}
+
+func planMermaid(plan contract.ScriptPlan) string {
+	quote := func(s string) string {
+		return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+	}
+
+	// Node IDs may contain characters Mermaid doesn't allow in IDs.
+	ids := map[string]string{}
+	for i, n := range plan.Nodes {
+		ids[n.Node] = fmt.Sprintf("n%d", i)
+	}
+
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "flowchart TD\n")
+
+	for _, n := range plan.Nodes {
+		fmt.Fprintf(b, "\t%s[%s]\n", ids[n.Node], quote(n.Node+"<br/>"+planRequest(n)))
+	}
+
+	for _, n := range plan.Nodes {
+		for _, d := range n.DependsOn {
+			fmt.Fprintf(b, "\t%s -->|%s| %s\n", ids[d.Node], quote(strings.Join(d.Reasons, ", ")), ids[n.Node])
+		}
+	}
+
+	return b.String()
+}
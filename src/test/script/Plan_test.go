@@ -0,0 +1,97 @@
+package script
+
+import (
+	"net/http"
+	"testing"
+
+	kin "github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Plan(T *testing.T) {
+	graph := NewExecutionGraph(log.NewPlain(0))
+
+	node := func(id string, opID string, method string, path string) {
+		op := &openapi3.Operation{
+			RequestMethod: method,
+			RequestPath:   path,
+			SpecOp:        &kin.Operation{OperationID: opID},
+		}
+
+		graph.AddNode(NewExecutionNode(op, id, &OperationRef{}, log.NewPlain(0)))
+	}
+
+	node("login", "pinLogin", http.MethodPost, "/login")
+	node("createPet", "addPet", http.MethodPost, "/pets")
+	node("getPet", "getPetById", http.MethodGet, "/pets/{petId}")
+
+	graph.AddDependency("createPet", "login", DependencySecurity)
+	graph.AddDependency("getPet", "createPet", DependencyData)
+	graph.AddDependency("getPet", "createPet", DependencyAfter)
+	graph.AddDependency("getPet", "createPet", DependencyData)
+	graph.AddDependency("getPet", "login", DependencyCondition)
+
+	plan, err := GetPlan("pets.yaml", graph)
+	assert.Nil(T, err)
+
+	T.Run("GetPlan", func(T *testing.T) {
+		assert.Equal(T, contract.ScriptPlan{
+			Script: "pets.yaml",
+			Nodes: []contract.ScriptPlanNode{
+				{
+					Node: "login", Operation: "pinLogin", Method: "POST", Path: "/login",
+					DependsOn: []contract.ScriptPlanDependency{},
+				},
+				{
+					Node: "createPet", Operation: "addPet", Method: "POST", Path: "/pets",
+					DependsOn: []contract.ScriptPlanDependency{
+						{Node: "login", Reasons: []string{DependencySecurity}},
+					},
+				},
+				{
+					Node: "getPet", Operation: "getPetById", Method: "GET", Path: "/pets/{petId}",
+					DependsOn: []contract.ScriptPlanDependency{
+						{Node: "createPet", Reasons: []string{DependencyData, DependencyAfter}},
+						{Node: "login", Reasons: []string{DependencyCondition}},
+					},
+				},
+			},
+		}, plan)
+	})
+
+	T.Run("DOT", func(T *testing.T) {
+		dot, err := ExportPlan(plan, PlanDOT)
+		assert.Nil(T, err)
+		assert.Equal(T, `digraph "pets.yaml" {
+	node [shape=box];
+	"login" [label="login\nPOST /login"];
+	"createPet" [label="createPet\nPOST /pets"];
+	"getPet" [label="getPet\nGET /pets/{petId}"];
+	"login" -> "createPet" [label="security"];
+	"createPet" -> "getPet" [label="data, after"];
+	"login" -> "getPet" [label="condition"];
+}
+`, dot)
+	})
+
+	T.Run("Mermaid", func(T *testing.T) {
+		mermaid, err := ExportPlan(plan, PlanMermaid)
+		assert.Nil(T, err)
+		assert.Equal(T, `flowchart TD
+	n0["login<br/>POST /login"]
+	n1["createPet<br/>POST /pets"]
+	n2["getPet<br/>GET /pets/{petId}"]
+	n0 -->|"security"| n1
+	n1 -->|"data, after"| n2
+	n0 -->|"condition"| n2
+`, mermaid)
+	})
+
+	T.Run("Unknown format", func(T *testing.T) {
+		_, err := ExportPlan(plan, "svg")
+		assert.NotNil(T, err)
+	})
+}
@@ -1,6 +1,10 @@
 package script
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
 	"github.com/x1n13y84issmd42/oasis/src/api"
 	"github.com/x1n13y84issmd42/oasis/src/contract"
@@ -10,11 +14,129 @@ import (
 
 // OperationRef is a node of execution graph as desfined in the script file.
 // It references a spec operation and contains the needed data.
+// ForEach references an array to execute the operation for every element of,
+// no more than Limit elements when it's set, see ForEach.
+// If is a condition to execute the operation on, see params.Condition.
+// OnSkipped tells what to do when a dependency is skipped by it's condition.
+// Assert are conditions over the results of other operations, a node
+// with assertions & no operationId makes no request, see Script.SetupAssertions().
+// Defaults set to false turns the script & spec defaults off for the operation.
 type OperationRef struct {
-	OperationID string           `yaml:"operationId"`
-	After       string           `yaml:"after"`
-	Use         OperationDataUse `yaml:"use"`
-	Expect      OperationDataUse `yaml:"expect"`
+	OperationID string              `yaml:"operationId"`
+	After       string              `yaml:"after"`
+	ForEach     string              `yaml:"forEach"`
+	Limit       int                 `yaml:"limit"`
+	If          string              `yaml:"if"`
+	OnSkipped   string              `yaml:"onSkipped"`
+	Assert      []string            `yaml:"assert"`
+	Defaults    *bool               `yaml:"defaults"`
+	Use         OperationDataUse    `yaml:"use"`
+	Expect      OperationDataExpect `yaml:"expect"`
+
+	// literal are the parameters with run time values substituted into them,
+	// like "path/petId", which are neither references nor templates.
+	literal map[string]bool
+}
+
+// Interpolate returns a copy of the operation reference with the run time
+// variables, like the forEach item, substituted into it's use & expect data.
+// The values are substituted as is, and the parameters they are substituted
+// into are literal, see OperationRef.IsLiteral().
+func (opRef *OperationRef) Interpolate(vars Vars) (*OperationRef, error) {
+	res := *opRef
+	res.literal = map[string]bool{}
+
+	for key, m := range map[string]*OperationDataMap{
+		"path":          &res.Use.Path,
+		"query":         &res.Use.Query,
+		"headers":       &res.Use.Headers,
+		"body":          &res.Use.Body,
+		"expect":        &res.Expect.Body,
+		"expectHeaders": &res.Expect.Headers,
+	} {
+		im := OperationDataMap{}
+		for k, v := range *m {
+			iv, err := vars.Substitute(v)
+			if err != nil {
+				return nil, err
+			}
+
+			if iv != v {
+				res.literal[key+"/"+k] = true
+			}
+
+			im[k] = iv
+		}
+
+		*m = im
+	}
+
+	res.Assert = []string{}
+	for _, a := range opRef.Assert {
+		ia, err := vars.Substitute(a)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Assert = append(res.Assert, ia)
+	}
+
+	return &res, nil
+}
+
+// IsLiteral tells whether the named parameter of the data block at key,
+// like "path", has run time values substituted into it, so it's used as is.
+func (opRef *OperationRef) IsLiteral(key string, name string) bool {
+	return opRef.literal[key+"/"+name]
+}
+
+// IsAssertion tells whether the operation reference is an assertion node,
+// which makes no request.
+func (opRef *OperationRef) IsAssertion() bool {
+	return opRef.OperationID == "" && len(opRef.Assert) > 0
+}
+
+// UsesDefaults tells whether the script & spec defaults are applied
+// to the operation reference. Assertion nodes make no requests, so they don't.
+func (opRef *OperationRef) UsesDefaults() bool {
+	return !opRef.IsAssertion() && (opRef.Defaults == nil || *opRef.Defaults)
+}
+
+// References returns the IDs of the operations referenced by opRef
+// in it's data, after, forEach, condition & assertions.
+func (opRef *OperationRef) References() []string {
+	res := []string{}
+
+	if opRef.After != "" {
+		res = append(res, opRef.After)
+	}
+
+	res = append(res, references(opRef.ForEach)...)
+
+	for _, c := range append([]string{opRef.If}, opRef.Assert...) {
+		if c == "" {
+			continue
+		}
+
+		if cond, err := params.ParseCondition(c); err == nil {
+			res = append(res, cond.References()...)
+		}
+	}
+
+	for _, m := range []OperationDataMap{
+		opRef.Use.Path,
+		opRef.Use.Query,
+		opRef.Use.Headers,
+		opRef.Use.Body,
+		opRef.Expect.Body,
+		opRef.Expect.Headers,
+	} {
+		for _, v := range m {
+			res = append(res, references(v)...)
+		}
+	}
+
+	return res
 }
 
 // OperationDataMap is a map of parameters for an OperationRef.
@@ -53,11 +175,24 @@ type OperationDataUse struct {
 }
 
 // OperationDataExpect corresponds to the 'expect' block of the OperationRef in a script file.
+// MinSize & MaxSize limit the response body size in bytes, SHA256 & MD5
+// are hex-encoded checksums of the response body.
 type OperationDataExpect struct {
 	Body    OperationDataMap `yaml:"body"`
 	Headers OperationDataMap `yaml:"headers"`
 	CT      string           `yaml:"CT"`
 	Status  int64            `yaml:"status"`
+	MinSize int64            `yaml:"minSize"`
+	MaxSize int64            `yaml:"maxSize"`
+	SHA256  string           `yaml:"sha256"`
+	MD5     string           `yaml:"md5"`
+}
+
+// ScriptEnvironment is a named set of variables & hosts, such as "staging",
+// which overrides the script's own ones.
+type ScriptEnvironment struct {
+	Vars  map[string]string `yaml:"vars"`
+	Hosts map[string]string `yaml:"hosts"`
 }
 
 // Script is a complex API testing scenario.
@@ -66,11 +201,154 @@ type OperationDataExpect struct {
 type Script struct {
 	api.OperationCache
 	contract.EntityTrait
-	SpecPaths  map[string]string                   `yaml:"specs"`
-	Securities map[string]*contract.ScriptSecurity `yaml:"security"`
-	Operations map[string]*OperationRef            `yaml:"operations"`
+	Specs       map[string]*ScriptSpec              `yaml:"specs"`
+	Defaults    *OperationDefaults                  `yaml:"defaults"`
+	Securities  map[string]*contract.ScriptSecurity `yaml:"security"`
+	Operations  map[string]*OperationRef            `yaml:"operations"`
+	Teardown    map[string]*OperationRef            `yaml:"teardown"`
+	Strict      bool                                `yaml:"strict"`
+	Parallelism int                                 `yaml:"parallelism"`
+	Sequential  bool                                `yaml:"sequential"`
+	Seed        *int64                              `yaml:"seed"`
+	OnSkipped   string                              `yaml:"onSkipped"`
+	Cleanup     string                              `yaml:"cleanup"`
+	Tags        []string                            `yaml:"tags"`
+
+	// Hosts are the API host names by spec names. The selected environment
+	// overrides them, as well as the variables.
+	Vars         map[string]string             `yaml:"vars"`
+	Hosts        map[string]string             `yaml:"hosts"`
+	Environments map[string]*ScriptEnvironment `yaml:"environments"`
+
+	// Dataset makes the script execute once per row. Every row gets
+	// it's own copy of the script, an iteration, see Script.Iteration().
+	Dataset    *ScriptDataset `yaml:"dataset"`
+	Iterations []*Script      `yaml:"-"`
+	Row        int            `yaml:"-"`
 
 	Sec map[string]*contract.SecurityAccess `yaml:-`
+
+	// source is the interpolated script file contents,
+	// vars are the variables of the selected environment, interpolated.
+	source []byte
+	vars   map[string]string
+
+	// graph & teardown are the built execution & teardown graphs, main is
+	// the one the teardown operations reference, see Script.GetTeardownGraph().
+	graph    *ExecutionGraph
+	teardown *ExecutionGraph
+	main     *ExecutionGraph
+}
+
+// IsStrict tells whether the script is to be executed in strict conformance mode.
+func (script *Script) IsStrict() bool {
+	return script.Strict
+}
+
+// ApplyDefaults merges the script & spec defaults into the operations,
+// the spec defaults take precedence over the script ones.
+// The operations are processed in the order of their names, so the cycles
+// check of OperationDefaults.Apply() sees the defaults applied before.
+func (script *Script) ApplyDefaults() {
+	for _, ops := range []map[string]*OperationRef{script.Operations, script.Teardown} {
+		opRefIDs := []string{}
+		for opRefID := range ops {
+			opRefIDs = append(opRefIDs, opRefID)
+		}
+
+		sort.Strings(opRefIDs)
+
+		for _, opRefID := range opRefIDs {
+			opRef := ops[opRefID]
+			if !opRef.UsesDefaults() {
+				continue
+			}
+
+			dependsOn := func(op2RefID string) bool {
+				return script.DependsOn(ops, op2RefID, opRefID)
+			}
+
+			if spec := script.Specs[strings.Split(opRef.OperationID, ".")[0]]; spec != nil {
+				spec.Defaults.Apply(opRef, dependsOn)
+			}
+
+			script.Defaults.Apply(opRef, dependsOn)
+		}
+	}
+}
+
+// DependsOn tells whether the opRefID operation depends on the op2RefID one,
+// directly or through other operations. The operations are looked up in ops,
+// then among the script operations, which the teardown ones may reference.
+func (script *Script) DependsOn(ops map[string]*OperationRef, opRefID string, op2RefID string) bool {
+	visited := map[string]bool{}
+
+	var walk func(id string) bool
+	walk = func(id string) bool {
+		if id == op2RefID {
+			return true
+		}
+
+		if visited[id] {
+			return false
+		}
+
+		visited[id] = true
+
+		opRef := ops[id]
+		if opRef == nil {
+			opRef = script.Operations[id]
+		}
+
+		if opRef == nil {
+			return false
+		}
+
+		for _, ref := range opRef.References() {
+			if walk(ref) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return walk(opRefID)
+}
+
+// GetParallelism returns the maximal number of operations executed at once,
+// zero means unlimited.
+func (script *Script) GetParallelism() int {
+	return script.Parallelism
+}
+
+// IsSequential tells whether the operations are to be executed one by one.
+func (script *Script) IsSequential() bool {
+	return script.Sequential
+}
+
+// GetRowParallelism returns the maximal number of dataset rows executed at once,
+// 1 by default, or zero for scripts without a dataset.
+func (script *Script) GetRowParallelism() int {
+	if script.Dataset == nil {
+		return 0
+	}
+
+	if script.Dataset.Parallelism <= 0 {
+		return 1
+	}
+
+	return script.Dataset.Parallelism
+}
+
+// NodeID returns an execution graph node ID for the opRefID,
+// which is prefixed with the row number in dataset iterations.
+func (script *Script) NodeID(opRefID string) string {
+	if script.Row > 0 {
+		return fmt.Sprintf("row%d.%s", script.Row, opRefID)
+	}
+
+	return opRefID
 }
 
 // GetExecutionGraph builds and returns an operation execution graph.
@@ -81,46 +359,116 @@ func (script *Script) GetExecutionGraph() gcontract.Graph {
 
 	graph := NewExecutionGraph(script.Log)
 
+	iterations := script.Iterations
+	if len(iterations) == 0 {
+		iterations = []*Script{script}
+	}
+
+	for _, it := range iterations {
+		if err := it.Build(graph); err != nil {
+			return NoGraph(err, script.Log)
+		}
+	}
+
+	script.graph = graph
+
+	return graph
+}
+
+// GetTeardownGraph builds and returns a graph of the teardown operations,
+// to be executed after the execution graph regardless of it's outcome.
+// Teardown operations may reference the execution graph operations,
+// without depending on them. Returns nil when there is nothing to tear down.
+func (script *Script) GetTeardownGraph() gcontract.Graph {
+	if len(script.Teardown) == 0 || script.graph == nil {
+		return nil
+	}
+
+	graph := NewExecutionGraph(script.Log)
+
+	iterations := script.Iterations
+	if len(iterations) == 0 {
+		iterations = []*Script{script}
+	}
+
+	for _, it := range iterations {
+		td := *it
+		td.Operations = it.Teardown
+		td.Sec = make(map[string]*contract.SecurityAccess)
+		td.main = script.graph
+
+		for opRefID := range td.Operations {
+			if it.Operations[opRefID] != nil {
+				return NoGraph(errors.Oops("The teardown operation '"+opRefID+"' has the same name as a script operation.", nil), script.Log)
+			}
+		}
+
+		if err := td.Build(graph); err != nil {
+			return NoGraph(err, script.Log)
+		}
+	}
+
+	script.teardown = graph
+
+	return graph
+}
+
+// Build adds the script operations to the execution graph.
+func (script *Script) Build(graph *ExecutionGraph) error {
 	for opRefID, opRef := range script.Operations {
 		//TODO: opRef.OperationID may be absent, use opRefID then.
-		op := script.GetOperation(opRef.OperationID)
-		opNode := script.GetNode(graph, opRefID, op, opRef)
+		opNode := script.GetNode(graph, opRefID, opRef)
+
+		if opRef.IsAssertion() {
+			err := script.SetupAssertions(graph, opRef, opNode, opRefID)
+			if err != nil {
+				return err
+			}
 
-		var err error
+			err = script.SetupCondition(graph, opRef, opNode, opRefID)
+			if err != nil {
+				return err
+			}
 
-		err = script.SetupDataDependency(graph, &opRef.Use.Path, opNode.Data.URL, opNode, opRef, opRefID)
-		if err != nil {
-			return NoGraph(err, script.Log)
+			err = script.SetupAfterDependency(graph, opRef, opNode)
+			if err != nil {
+				return err
+			}
+
+			continue
 		}
 
-		err = script.SetupDataDependency(graph, &opRef.Use.Query, opNode.Data.Query, opNode, opRef, opRefID)
-		if err != nil {
-			return NoGraph(err, script.Log)
+		if len(opRef.Assert) > 0 {
+			return errors.Oops("The '"+opRefID+"' operation has both an operationId and assertions, assertion nodes make no requests.", nil)
 		}
 
-		err = script.SetupDataDependency(graph, &opRef.Use.Headers, opNode.Data.Headers, opNode, opRef, opRefID)
+		err := script.SetupData(graph, opRef, opNode, opRefID)
 		if err != nil {
-			return NoGraph(err, script.Log)
+			return err
 		}
 
-		err = script.SetupDataDependency(graph, &opRef.Use.Body, opNode.Data.Body, opNode, opRef, opRefID)
-		if err != nil {
-			return NoGraph(err, script.Log)
+		if opRef.ForEach != "" {
+			err = script.SetupForEach(graph, opRef, opNode, opRefID)
+			if err != nil {
+				return err
+			}
+		} else if _, err = opRef.Interpolate(Vars{}); err != nil {
+			return errors.Oops("The '"+opRefID+"' operation has no forEach to take the item from.", err)
 		}
 
-		err = script.SetupDataDependency(graph, &opRef.Expect.Body, opNode.ExpectBody, opNode, opRef, opRefID)
+		err = script.SetupCondition(graph, opRef, opNode, opRefID)
 		if err != nil {
-			return NoGraph(err, script.Log)
+			return err
 		}
 
 		err = script.SetupAfterDependency(graph, opRef, opNode)
 		if err != nil {
-			return NoGraph(err, script.Log)
+			return err
 		}
 
 		err = script.SetupSecurityDependency(graph, opRef, opNode)
 		if err != nil {
-			return NoGraph(err, script.Log)
+			return err
 		}
 	}
 
@@ -130,38 +478,75 @@ func (script *Script) GetExecutionGraph() gcontract.Graph {
 	// 	return NoGraph(errors.GraphHasCycles(cycle, nil), script.Log)
 	// }
 
-	return graph
+	return nil
 }
 
-// SetupDependency adds an edge to the execution graph between two spec nodes.
+// SetupData loads the opRef data into the node, adding edges
+// to the operations it references.
+func (script *Script) SetupData(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	err := script.SetupDataDependency(graph, &opRef.Use.Path, "path", opNode.Data.URL, opNode, opRef, opRefID)
+	if err != nil {
+		return err
+	}
+
+	err = script.SetupDataDependency(graph, &opRef.Use.Query, "query", opNode.Data.Query, opNode, opRef, opRefID)
+	if err != nil {
+		return err
+	}
+
+	err = script.SetupDataDependency(graph, &opRef.Use.Headers, "headers", opNode.Data.Headers, opNode, opRef, opRefID)
+	if err != nil {
+		return err
+	}
+
+	err = script.SetupDataDependency(graph, &opRef.Use.Body, "body", opNode.Data.Body, opNode, opRef, opRefID)
+	if err != nil {
+		return err
+	}
+
+	return script.SetupDataDependency(graph, &opRef.Expect.Body, "expect", opNode.ExpectBody, opNode, opRef, opRefID)
+}
+
+// SetupDependency adds an edge to the execution graph between two spec nodes,
+// the reason tells why opNode depends on the other one, see the Dependency* constants.
+// Nodes outside of the graph, such as forEach items, get no edges,
+// as their dependencies are those of the forEach node.
 func (script *Script) SetupDependency(
 	scriptNodeName string,
 	graph *ExecutionGraph,
 	opRef *OperationRef,
 	opNode *ExecutionNode,
+	reason string,
 ) (contract.Operation, error) {
 	opRef2 := script.Operations[scriptNodeName]
-	if opRef2 == nil {
-		return nil, errors.NotFound("Operation reference", scriptNodeName, nil)
+	if opRef2 == nil && script.main != nil {
+		// Teardown operations require the results of the main graph operations.
+		if _opNode2 := script.main.Node(gcontract.NodeID(script.NodeID(scriptNodeName))); _opNode2 != nil {
+			opNode2 := _opNode2.(*ExecutionNode)
+			opNode.Requires = append(opNode.Requires, opNode2)
+			return opNode2.Operation, nil
+		}
 	}
 
-	op2 := script.GetOperation(opRef2.OperationID)
 	if opRef2 == nil {
-		return nil, errors.NotFound("Spec operation", opRef2.OperationID, nil)
+		return nil, errors.NotFound("Operation reference", scriptNodeName, nil)
 	}
 
 	// Adding an edge to the execution graph.
-	graph.AddEdge(opNode.ID(), script.GetNode(graph, scriptNodeName, op2, opRef2).ID())
+	opNode2 := script.GetNode(graph, scriptNodeName, opRef2)
+	if graph.Node(opNode.ID()) != nil {
+		graph.AddDependency(opNode.ID(), opNode2.ID(), reason)
+	}
 
-	return op2, nil
+	return opNode2.Operation, nil
 }
 
 // SetupSecurityDependency adds an edge to the execution graph if opRef has an 'after' specified.
 func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
-	refdep := func(p *contract.ParameterAccess, v string) error {
+	refdep := func(p *contract.ParameterAccess, v string, key string) error {
 		isref, op2RefID, selector := Dereference(v)
 		if isref {
-			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode)
+			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, DependencySecurity)
 
 			if err != nil {
 				return err
@@ -174,6 +559,14 @@ func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *Oper
 				Selector: selector,
 				Log:      script.Log,
 			}).Value()
+		} else if params.IsTemplate(v) {
+			ctx := script.TemplateContext(key)
+			tpl, err := script.SetupTemplateDependency(v, ctx, graph, opRef, opNode, DependencySecurity)
+			if err != nil {
+				return err
+			}
+
+			(*p) = tpl.Value(ctx)
 		} else {
 			(*p) = params.Value(v)
 		}
@@ -191,22 +584,22 @@ func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *Oper
 		if opNodeSec.GetName() == secName {
 			script.Sec[secName] = &contract.SecurityAccess{}
 
-			err = refdep(&script.Sec[secName].Value, sec.Value)
+			err = refdep(&script.Sec[secName].Value, sec.Value, script.NodeID("security/"+secName+"/value"))
 			if err != nil {
 				return err
 			}
 
-			err = refdep(&script.Sec[secName].Token, sec.Token)
+			err = refdep(&script.Sec[secName].Token, sec.Token, script.NodeID("security/"+secName+"/token"))
 			if err != nil {
 				return err
 			}
 
-			err = refdep(&script.Sec[secName].Username, sec.Username)
+			err = refdep(&script.Sec[secName].Username, sec.Username, script.NodeID("security/"+secName+"/username"))
 			if err != nil {
 				return err
 			}
 
-			err = refdep(&script.Sec[secName].Password, sec.Password)
+			err = refdep(&script.Sec[secName].Password, sec.Password, script.NodeID("security/"+secName+"/password"))
 			if err != nil {
 				return err
 			}
@@ -219,10 +612,87 @@ func (script *Script) SetupSecurityDependency(graph *ExecutionGraph, opRef *Oper
 	return nil
 }
 
+// SetupCondition parses the opRef condition, and adds edges between the operations
+// referenced in it & opNode. It also sets the node's reaction to skipped dependencies.
+func (script *Script) SetupCondition(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	opNode.OnSkipped = OnSkippedSkip
+
+	for _, onSkipped := range []string{script.OnSkipped, opRef.OnSkipped} {
+		switch onSkipped {
+		case "":
+		case OnSkippedSkip, OnSkippedRun:
+			opNode.OnSkipped = onSkipped
+		default:
+			return errors.Oops(fmt.Sprintf("The onSkipped of '%s' must be either %s or %s, got '%s'.", opRefID, OnSkippedSkip, OnSkippedRun, onSkipped), nil)
+		}
+	}
+
+	if opRef.If == "" {
+		return nil
+	}
+
+	cond, err := params.ParseCondition(opRef.If)
+	if err != nil {
+		return err
+	}
+
+	ctx := script.TemplateContext(string(opNode.ID()) + "/if")
+
+	for _, op2RefID := range cond.References() {
+		op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, DependencyCondition)
+		if err != nil {
+			return err
+		}
+
+		ctx.Results[op2RefID] = op2.Result()
+	}
+
+	opNode.If = cond
+	opNode.IfContext = ctx
+
+	return nil
+}
+
+// SetupAssertions parses the opRef assertions, and adds edges between the operations
+// referenced in them & opNode, which evaluates them instead of making a request.
+func (script *Script) SetupAssertions(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode, opRefID string) error {
+	if opRef.ForEach != "" {
+		return errors.Oops("The '"+opRefID+"' assertion node can't have a forEach.", nil)
+	}
+
+	if _, err := opRef.Interpolate(Vars{}); err != nil {
+		return errors.Oops("The '"+opRefID+"' assertion node uses a run time variable, but ${item} & ${index} aren't available in assertion nodes, as they can't have a forEach.", err)
+	}
+
+	ctx := script.TemplateContext(string(opNode.ID()) + "/assert")
+
+	for _, a := range opRef.Assert {
+		cond, err := params.ParseCondition(a)
+		if err != nil {
+			return err
+		}
+
+		for _, op2RefID := range cond.References() {
+			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, DependencyAssert)
+			if err != nil {
+				return err
+			}
+
+			ctx.Results[op2RefID] = op2.Result()
+		}
+
+		opNode.Assert = append(opNode.Assert, cond)
+	}
+
+	opNode.AssertContext = ctx
+
+	return nil
+}
+
 // SetupAfterDependency adds an edge to the execution graph if opRef has an 'after' specified.
 func (script *Script) SetupAfterDependency(graph *ExecutionGraph, opRef *OperationRef, opNode *ExecutionNode) error {
 	if opRef.After != "" {
-		_, err := script.SetupDependency(opRef.After, graph, opRef, opNode)
+		_, err := script.SetupDependency(opRef.After, graph, opRef, opNode, DependencyAfter)
 		return err
 	}
 
@@ -234,6 +704,7 @@ func (script *Script) SetupAfterDependency(graph *ExecutionGraph, opRef *Operati
 func (script *Script) SetupDataDependency(
 	graph *ExecutionGraph,
 	srcParams *OperationDataMap,
+	key string,
 	dstParams contract.Set,
 	opNode *ExecutionNode,
 	opRef *OperationRef,
@@ -241,17 +712,31 @@ func (script *Script) SetupDataDependency(
 ) error {
 	refParams := params.NewReferenceSource(script.Log)
 	memParams := params.NewMemorySource("script data")
+	tplParams := params.NewMemorySource("script templates")
+	tplContext := script.TemplateContext(string(opNode.ID()) + "/" + key)
 
 	for pn, pv := range *srcParams {
+		if opRef.IsLiteral(key, pn) {
+			memParams.Add(pn, pv)
+			continue
+		}
+
 		isref, op2RefID, selector := Dereference(pv)
 		if isref {
-			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode)
+			op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, DependencyData)
 			if err != nil {
 				return err
 			}
 
 			// Adding the value so it's available for op later.
 			refParams.AddReference(pn, op2.ID()+" node", op2.Result(), selector)
+		} else if params.IsTemplate(pv) {
+			_, err := script.SetupTemplateDependency(pv, tplContext, graph, opRef, opNode, DependencyData)
+			if err != nil {
+				return err
+			}
+
+			tplParams.Add(pn, pv)
 		} else {
 			memParams.Add(pn, pv)
 		}
@@ -259,26 +744,92 @@ func (script *Script) SetupDataDependency(
 
 	dstParams.Load(refParams)
 	dstParams.Load(memParams)
+	dstParams.Load(params.Templates(tplParams, tplContext))
 
 	return nil
 }
 
+// SetupTemplateDependency parses a template value, adds edges between
+// the operations referenced in it & opNode, and makes their results
+// available to the template via ctx.
+func (script *Script) SetupTemplateDependency(
+	v string,
+	ctx *params.TemplateContext,
+	graph *ExecutionGraph,
+	opRef *OperationRef,
+	opNode *ExecutionNode,
+	reason string,
+) (*params.Template, error) {
+	tpl, err := params.ParseTemplate(v)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op2RefID := range tpl.References() {
+		op2, err := script.SetupDependency(op2RefID, graph, opRef, opNode, reason)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.Results[op2RefID] = op2.Result()
+	}
+
+	return tpl, nil
+}
+
 // GetNode returns an ExecutionNode instance corresponding to the opRefID.
 // If such a node exists in the graph, it will be returned, otherwise a new
-// node is created.
-func (script *Script) GetNode(graph gcontract.Graph, opRefID string, op contract.Operation, opRef *OperationRef) *ExecutionNode {
+// node is created with it's own instance of the spec operation.
+func (script *Script) GetNode(graph gcontract.Graph, opRefID string, opRef *OperationRef) *ExecutionNode {
 	var opNode *ExecutionNode
-	_opNode := graph.Node(gcontract.NodeID(opRefID))
+	nodeID := script.NodeID(opRefID)
+	_opNode := graph.Node(gcontract.NodeID(nodeID))
 	if _opNode != nil {
 		opNode = _opNode.(*ExecutionNode)
 	} else {
-		opNode = NewExecutionNode(op, opRefID, opRef, script.Log)
+		var op contract.Operation
+		if opRef.IsAssertion() {
+			op = NewAssertion(nodeID, script.Log)
+		} else {
+			op = script.NewOperation(opRef.OperationID)
+		}
+
+		opNode = NewExecutionNode(op, nodeID, opRef, script.Log)
+		opNode.Spec = strings.Split(opRef.OperationID, ".")[0]
+		opNode.Host = script.Hosts[opNode.Spec]
+		opNode.Row = script.Row
+		opNode.Sec = script.Sec
 		graph.AddNode(opNode)
 	}
 
 	return opNode
 }
 
+// TemplateContext creates a new template context for the templates at key,
+// like "getPet/body", seeded with the script seed.
+func (script *Script) TemplateContext(key string) *params.TemplateContext {
+	ctx := params.NewTemplateContext(script.Log)
+	ctx.Seed = script.Seed
+	ctx.Key = key
+
+	return ctx
+}
+
+// SetSeed overrides the script seed, along with the dataset iterations' ones.
+func (script *Script) SetSeed(seed int64) {
+	script.Seed = &seed
+
+	for _, it := range script.Iterations {
+		it.Seed = &seed
+	}
+}
+
+// GetVars returns the script variables of the selected environment,
+// with the variables referenced in their values interpolated.
+func (script *Script) GetVars() map[string]string {
+	return script.vars
+}
+
 // GetSecurity returns the script's security parameters.
 func (script *Script) GetSecurity(name string) *contract.SecurityAccess {
 	return script.Sec[name]
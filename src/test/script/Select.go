@@ -0,0 +1,142 @@
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// SelectOnly returns a graph of the named nodes along with all the nodes
+// they depend on, transitively, so their references resolve.
+func SelectOnly(graph gcontract.Graph, names []string) gcontract.Graph {
+	eg, ok := graph.(*ExecutionGraph)
+	if !ok {
+		return graph
+	}
+
+	start, err := FindNodes(eg, names)
+	if err != nil {
+		return NoGraph(err, eg.Log)
+	}
+
+	return eg.Subgraph(closure(start, eg.AdjacentNodes))
+}
+
+// SelectFrom returns a graph of the named nodes, the nodes depending on them,
+// transitively, and all the nodes those depend on.
+func SelectFrom(graph gcontract.Graph, names []string) gcontract.Graph {
+	eg, ok := graph.(*ExecutionGraph)
+	if !ok {
+		return graph
+	}
+
+	dependents, err := Dependents(eg, names)
+	if err != nil {
+		return NoGraph(err, eg.Log)
+	}
+
+	start := []gcontract.NodeID{}
+	for nID := range dependents {
+		start = append(start, nID)
+	}
+
+	return eg.Subgraph(closure(start, eg.AdjacentNodes))
+}
+
+// Dependents returns the IDs of the named nodes and the nodes depending on them, transitively.
+func Dependents(graph *ExecutionGraph, names []string) (map[gcontract.NodeID]bool, error) {
+	start, err := FindNodes(graph, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return closure(start, graph.UpstreamNodes), nil
+}
+
+// FindNodes returns the IDs of the nodes with the names. A name is a script
+// operation name, which matches the node in every dataset row,
+// or a fragment instance name, which matches all of it's operations.
+func FindNodes(graph *ExecutionGraph, names []string) ([]gcontract.NodeID, error) {
+	res := []gcontract.NodeID{}
+
+	for _, name := range names {
+		found := false
+
+		for _n := range graph.Nodes().Range() {
+			n := _n.(*ExecutionNode)
+			id := string(n.ID())
+
+			if n.Row > 0 {
+				id = strings.TrimPrefix(id, fmt.Sprintf("row%d.", n.Row))
+			}
+
+			if id == name || strings.HasPrefix(id, name+".") {
+				res = append(res, n.ID())
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, errors.NotFound("Script node", name, nil)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+
+	return res, nil
+}
+
+// Subgraph returns a graph of the nodes with the IDs, and the edges between them.
+func (graph *ExecutionGraph) Subgraph(nIDs map[gcontract.NodeID]bool) *ExecutionGraph {
+	sub := NewExecutionGraph(graph.Log)
+	nodes := []gcontract.Node{}
+
+	for _n := range graph.Nodes().Range() {
+		if nIDs[_n.ID()] {
+			sub.AddNode(_n)
+			nodes = append(nodes, _n)
+		}
+	}
+
+	for _, n := range nodes {
+		for an := range graph.AdjacentNodes(n.ID()).Range() {
+			if nIDs[an.ID()] {
+				sub.AddEdge(n.ID(), an.ID())
+
+				for _, reason := range graph.Reasons(n.ID(), an.ID()) {
+					sub.AddDependency(n.ID(), an.ID(), reason)
+				}
+			}
+		}
+	}
+
+	return sub
+}
+
+// closure returns the IDs of the start nodes and the nodes reachable from them.
+func closure(start []gcontract.NodeID, next func(gcontract.NodeID) gcontract.Nodes) map[gcontract.NodeID]bool {
+	res := map[gcontract.NodeID]bool{}
+	queue := append([]gcontract.NodeID{}, start...)
+
+	for len(queue) > 0 {
+		nID := queue[0]
+		queue = queue[1:]
+
+		if res[nID] {
+			continue
+		}
+
+		res[nID] = true
+
+		for n := range next(nID).Range() {
+			queue = append(queue, n.ID())
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,65 @@
+package script
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Select(T *testing.T) {
+	graph := func() *ExecutionGraph {
+		return orderGraph([][2]string{
+			{"createTX", "login.pinLogin"},
+			{"POSScan", "createTX"},
+			{"acceptDossier", "POSScan"},
+			{"getStatus", "createTX"},
+			{"listPets", "login.pinLogin"},
+		}, "login.pinLogin", "createTX", "POSScan", "acceptDossier", "getStatus", "listPets", "unrelated")
+	}
+
+	ids := func(graph gcontract.Graph) []string {
+		order, err := ExecutionOrder(graph)
+		assert.Nil(T, err)
+		return orderIDs(order)
+	}
+
+	T.Run("Only", func(T *testing.T) {
+		assert.Equal(T, []string{"login.pinLogin", "createTX", "POSScan"}, ids(SelectOnly(graph(), []string{"POSScan"})))
+		assert.Equal(T, []string{"login.pinLogin", "listPets", "unrelated"}, ids(SelectOnly(graph(), []string{"listPets", "unrelated"})))
+	})
+
+	T.Run("From", func(T *testing.T) {
+		assert.Equal(T, []string{"login.pinLogin", "createTX", "POSScan", "acceptDossier"}, ids(SelectFrom(graph(), []string{"POSScan"})))
+		assert.Equal(T, []string{"login.pinLogin", "createTX", "POSScan", "acceptDossier", "getStatus"}, ids(SelectFrom(graph(), []string{"createTX"})))
+	})
+
+	T.Run("Fragment instance", func(T *testing.T) {
+		assert.Equal(T, []string{"login.pinLogin"}, ids(SelectOnly(graph(), []string{"login"})))
+		assert.Len(T, ids(SelectFrom(graph(), []string{"login"})), 6)
+	})
+
+	T.Run("Dataset rows", func(T *testing.T) {
+		graph := NewExecutionGraph(log.NewPlain(0))
+
+		for _, row := range []int{1, 2} {
+			for _, n := range []string{"login", "getUser", "listUsers"} {
+				graph.AddNode(&ExecutionNode{OpRefID: fmt.Sprintf("row%d.%s", row, n), Row: row})
+			}
+
+			graph.AddEdge(gcontract.NodeID(fmt.Sprintf("row%d.getUser", row)), gcontract.NodeID(fmt.Sprintf("row%d.login", row)))
+		}
+
+		assert.Equal(T, []string{"row1.login", "row1.getUser", "row2.login", "row2.getUser"}, ids(SelectOnly(graph, []string{"getUser"})))
+	})
+
+	T.Run("Not found", func(T *testing.T) {
+		_, ok := SelectOnly(graph(), []string{"nope"}).(*NullGraph)
+		assert.True(T, ok)
+
+		_, err := FindNodes(graph(), []string{"createTX", "nope"})
+		assert.NotNil(T, err)
+	})
+}
@@ -0,0 +1,262 @@
+package script
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+// RunState is the persisted state of a script execution: the resolved script
+// variables, and the statuses & results of the executed nodes. It is saved
+// as the script progresses, so the execution may be resumed later,
+// without executing the passed nodes again, see RunState.Restore().
+type RunState struct {
+	Script string                `json:"script"`
+	Vars   map[string]string     `json:"vars"`
+	Nodes  map[string]*NodeState `json:"nodes"`
+
+	// Path is the file the state is saved to.
+	Path  string `json:"-"`
+	mutex sync.Mutex
+}
+
+// NodeState is the persisted outcome of a script node.
+type NodeState struct {
+	Status   string         `json:"status"`
+	Request  *RequestState  `json:"request,omitempty"`
+	Response *ResponseState `json:"response,omitempty"`
+}
+
+// RequestState is a persisted HTTP request, with the secret values masked.
+type RequestState struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+}
+
+// ResponseState is a persisted HTTP response. Bodies which are not valid
+// UTF-8 are base64-encoded, which is told by the Encoding field.
+// Results without HTTP responses, like the forEach aggregates,
+// have only the body.
+type ResponseState struct {
+	Status   int         `json:"status,omitempty"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Body     string      `json:"body"`
+	Encoding string      `json:"encoding,omitempty"`
+}
+
+// NewRunState creates a new RunState instance, which is saved to path.
+func NewRunState(path string, script string, vars map[string]string) *RunState {
+	state := &RunState{
+		Script: filepath.Clean(script),
+		Vars:   map[string]string{},
+		Nodes:  map[string]*NodeState{},
+		Path:   path,
+	}
+
+	for k, v := range vars {
+		state.Vars[k] = log.Mask(v)
+	}
+
+	return state
+}
+
+// LoadRunState loads a state saved to path. The state must have been saved
+// for the same script, with the same variables.
+func LoadRunState(path string, script string, vars map[string]string) (*RunState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Oops("Failed to read the run state '"+path+"'.", err)
+	}
+
+	state := &RunState{Path: path}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.Oops("Failed to parse the run state '"+path+"'.", err)
+	}
+
+	if state.Script != filepath.Clean(script) {
+		return nil, errors.Oops("The run state '"+path+"' has been saved for the script '"+state.Script+"'.", nil)
+	}
+
+	names := []string{}
+	for k := range vars {
+		names = append(names, k)
+	}
+
+	sort.Strings(names)
+
+	for _, k := range names {
+		if v, ok := state.Vars[k]; ok && v != log.Mask(vars[k]) {
+			return nil, errors.Oops("The script variable '"+k+"' has changed since the run state '"+path+"' has been saved.", nil)
+		}
+	}
+
+	if state.Nodes == nil {
+		state.Nodes = map[string]*NodeState{}
+	}
+
+	return state, nil
+}
+
+// Restore rehydrates the results of the graph nodes which have passed,
+// except the rerun ones, so their references resolve without executing them.
+// The restored nodes are marked as such, see ExecutionNode.Restored.
+func (state *RunState) Restore(graph gcontract.Graph, rerun map[gcontract.NodeID]bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for _n := range graph.Nodes().Range() {
+		n := _n.(*ExecutionNode)
+
+		ns := state.Nodes[string(n.ID())]
+		if ns == nil || ns.Status != contract.NodePassed || rerun[n.ID()] {
+			continue
+		}
+
+		result := n.Operation.Result()
+		result.Success = true
+
+		if ns.Request != nil {
+			u, _ := url.Parse(ns.Request.URL)
+			if u == nil {
+				u = &url.URL{}
+			}
+
+			result.HTTPRequest = &http.Request{
+				Method: ns.Request.Method,
+				URL:    u,
+				Header: ns.Request.Headers,
+			}
+		}
+
+		if ns.Response != nil {
+			if ns.Response.Status != 0 {
+				result.HTTPResponse = &http.Response{
+					Status:     fmt.Sprintf("%d %s", ns.Response.Status, http.StatusText(ns.Response.Status)),
+					StatusCode: ns.Response.Status,
+					Proto:      "HTTP/1.1",
+					Header:     ns.Response.Headers,
+				}
+			}
+
+			result.ResponseBytes = []byte(ns.Response.Body)
+			if ns.Response.Encoding == "base64" {
+				result.ResponseBytes, _ = base64.StdEncoding.DecodeString(ns.Response.Body)
+			}
+		}
+
+		n.Result = result
+		n.Restored = true
+	}
+}
+
+// Record stores the outcome of the executed node, nodes restored
+// from the state keep their state.
+func (state *RunState) Record(n *ExecutionNode) {
+	if n.Restored {
+		return
+	}
+
+	ns := &NodeState{Status: contract.NodePassed}
+
+	if n.Skipped {
+		ns.Status = contract.NodeSkipped
+	} else if n.Result == nil || !n.Result.Success {
+		ns.Status = contract.NodeFailed
+	}
+
+	if res := n.Result; res != nil {
+		if res.HTTPRequest != nil && res.HTTPRequest.URL != nil {
+			ns.Request = &RequestState{
+				Method:  res.HTTPRequest.Method,
+				URL:     log.Mask(res.HTTPRequest.URL.String()),
+				Headers: maskHeaders(res.HTTPRequest.Header),
+			}
+		}
+
+		if res.HTTPResponse != nil || res.Size() > 0 {
+			ns.Response = &ResponseState{}
+
+			if res.HTTPResponse != nil {
+				ns.Response.Status = res.HTTPResponse.StatusCode
+				ns.Response.Headers = res.HTTPResponse.Header
+			}
+		}
+
+		if res.Size() > 0 {
+			if body, err := res.Body(); err == nil {
+				data, _ := ioutil.ReadAll(body)
+				body.Close()
+
+				ns.Response.Body = string(data)
+				if !utf8.Valid(data) {
+					ns.Response.Body = base64.StdEncoding.EncodeToString(data)
+					ns.Response.Encoding = "base64"
+				}
+			}
+		}
+	}
+
+	state.mutex.Lock()
+	state.Nodes[string(n.ID())] = ns
+	state.mutex.Unlock()
+}
+
+// Save writes the state to it's file. The file is replaced at once,
+// so it's never left half-written.
+func (state *RunState) Save() error {
+	state.mutex.Lock()
+	data, err := json.MarshalIndent(state, "", "  ")
+	state.mutex.Unlock()
+
+	if err != nil {
+		return errors.Oops("Failed to save the run state '"+state.Path+"'.", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(state.Path), filepath.Base(state.Path)+".*")
+	if err != nil {
+		return errors.Oops("Failed to save the run state '"+state.Path+"'.", err)
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), state.Path)
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Oops("Failed to save the run state '"+state.Path+"'.", err)
+	}
+
+	return nil
+}
+
+// maskHeaders returns a copy of the headers with the secret values
+// & the log.SecretHeaders masked.
+func maskHeaders(headers http.Header) http.Header {
+	res := http.Header{}
+	for k, vs := range headers {
+		for _, v := range vs {
+			res[k] = append(res[k], log.MaskHeader(k, v))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,157 @@
+package script
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	kin "github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	gcontract "github.com/x1n13y84issmd42/gog/graph/contract"
+	"github.com/x1n13y84issmd42/oasis/src/api"
+	"github.com/x1n13y84issmd42/oasis/src/api/openapi3"
+	"github.com/x1n13y84issmd42/oasis/src/contract"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+	"github.com/x1n13y84issmd42/oasis/src/params"
+	"github.com/x1n13y84issmd42/oasis/src/test"
+)
+
+func Test_RunState(T *testing.T) {
+	node := func(id string) *ExecutionNode {
+		op := &openapi3.Operation{
+			OperationPrototype: api.NewOperationPrototype(log.NewPlain(0)),
+			SpecOp:             &kin.Operation{OperationID: id},
+		}
+
+		return NewExecutionNode(op, id, &OperationRef{}, log.NewPlain(0))
+	}
+
+	executed := func(id string, status int, body []byte) *ExecutionNode {
+		n := node(id)
+		n.Result = &contract.OperationResult{
+			Success:       status < 400,
+			HTTPRequest:   &http.Request{Method: http.MethodPost, URL: &url.URL{Scheme: "http", Host: "example.com", Path: "/pets"}},
+			HTTPResponse:  &http.Response{StatusCode: status, Header: http.Header{"Content-Type": {"application/json"}}},
+			ResponseBytes: body,
+		}
+
+		return n
+	}
+
+	vars := map[string]string{"mobile": "+380632700628"}
+
+	save := func(T *testing.T, nodes ...*ExecutionNode) string {
+		path := filepath.Join(T.TempDir(), "state.json")
+		state := NewRunState(path, "script/credit.yaml", vars)
+
+		for _, n := range nodes {
+			state.Record(n)
+		}
+
+		assert.Nil(T, state.Save())
+
+		return path
+	}
+
+	T.Run("Restore", func(T *testing.T) {
+		skipped := node("skipped")
+		skipped.Skipped = true
+
+		path := save(T,
+			executed("login", 200, []byte(`{"token":"secret"}`)),
+			executed("createTX", 201, []byte{0xff, 0x00}),
+			executed("POSScan", 500, []byte(`{}`)),
+			skipped,
+		)
+
+		state, err := LoadRunState(path, "./script/credit.yaml", vars)
+		assert.Nil(T, err)
+		assert.Equal(T, contract.NodeFailed, state.Nodes["POSScan"].Status)
+		assert.Equal(T, contract.NodeSkipped, state.Nodes["skipped"].Status)
+
+		graph := NewExecutionGraph(log.NewPlain(0))
+		for _, id := range []string{"login", "createTX", "POSScan", "skipped", "new"} {
+			graph.AddNode(node(id))
+		}
+
+		state.Restore(graph, nil)
+
+		login := graph.Node("login").(*ExecutionNode)
+		assert.True(T, login.Restored)
+		assert.Same(T, login.Operation.Result(), login.Result)
+		assert.Equal(T, "/pets", login.Result.HTTPRequest.URL.Path)
+
+		doc, err := test.Document(login.Result)
+		assert.Nil(T, err)
+		assert.Equal(T, map[string]interface{}{"token": "secret"}, doc)
+
+		createTX := graph.Node("createTX").(*ExecutionNode)
+		assert.True(T, createTX.Restored)
+		assert.Equal(T, []byte{0xff, 0x00}, createTX.Result.ResponseBytes)
+		assert.Equal(T, http.StatusCreated, createTX.Result.HTTPResponse.StatusCode)
+
+		for _, id := range []string{"POSScan", "skipped", "new"} {
+			assert.False(T, graph.Node(gcontract.NodeID(id)).(*ExecutionNode).Restored)
+		}
+	})
+
+	T.Run("Rerun", func(T *testing.T) {
+		path := save(T, executed("login", 200, []byte(`{}`)), executed("createTX", 201, []byte(`{}`)))
+
+		state, err := LoadRunState(path, "script/credit.yaml", vars)
+		assert.Nil(T, err)
+
+		graph := NewExecutionGraph(log.NewPlain(0))
+		graph.AddNode(node("login"))
+		graph.AddNode(node("createTX"))
+
+		state.Restore(graph, map[gcontract.NodeID]bool{"createTX": true})
+
+		assert.True(T, graph.Node("login").(*ExecutionNode).Restored)
+		assert.False(T, graph.Node("createTX").(*ExecutionNode).Restored)
+	})
+
+	T.Run("ForEach", func(T *testing.T) {
+		getPet := node("getPet")
+		getPet.Items = []*ExecutionNode{
+			executed("getPet[0]", 200, []byte(`{"name":"Rex"}`)),
+			executed("getPet[1]", 200, []byte(`{"name":"Tom"}`)),
+		}
+		getPet.Result = (&ForEach{}).Aggregate(getPet.Operation.Result(), getPet.Items)
+
+		state, err := LoadRunState(save(T, getPet), "script/credit.yaml", vars)
+		assert.Nil(T, err)
+
+		graph := NewExecutionGraph(log.NewPlain(0))
+		graph.AddNode(node("getPet"))
+
+		state.Restore(graph, nil)
+
+		restored := graph.Node("getPet").(*ExecutionNode)
+		assert.True(T, restored.Restored)
+		assert.Nil(T, restored.Result.HTTPResponse)
+
+		ref := params.Reference{
+			OpID:     "getPet",
+			Result:   restored.Result,
+			Selector: "[1].name",
+			Log:      log.NewPlain(0),
+		}
+
+		assert.Equal(T, "Tom", ref.Value()())
+	})
+
+	T.Run("Other script", func(T *testing.T) {
+		_, err := LoadRunState(save(T), "script/debit.yaml", vars)
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Changed vars", func(T *testing.T) {
+		_, err := LoadRunState(save(T), "script/credit.yaml", map[string]string{"mobile": "+380000000000"})
+		assert.NotNil(T, err)
+
+		_, err = LoadRunState(save(T), "script/credit.yaml", map[string]string{"pin": "111"})
+		assert.Nil(T, err)
+	})
+}
@@ -0,0 +1,123 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+)
+
+// SuiteFilter selects the scripts of a suite. Include & Exclude are glob
+// patterns, like "credit-*.yaml" or "noosa/*", matched against the script
+// paths relative to the suite directory, and the file names.
+// Tags select the scripts having any of them, the tags prefixed with "!"
+// exclude the scripts having them.
+type SuiteFilter struct {
+	Include []string
+	Exclude []string
+	Tags    []string
+}
+
+// DiscoverScripts returns the sorted paths of the script files in dir
+// & it's subdirectories, selected by the filter. The files without
+// operations, like the ones with fragments to include, are left out.
+func DiscoverScripts(dir string, filter SuiteFilter) ([]string, error) {
+	paths := []string{}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if len(filter.Include) > 0 && !matchGlobs(filter.Include, rel) {
+			return nil
+		}
+
+		if matchGlobs(filter.Exclude, rel) {
+			return nil
+		}
+
+		fileData, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// Only these fields are read, the rest may contain variables yet.
+		script := struct {
+			Operations map[string]interface{} `yaml:"operations"`
+			Tags       []string               `yaml:"tags"`
+		}{}
+
+		if err := yaml.Unmarshal(fileData, &script); err != nil || len(script.Operations) == 0 {
+			return nil
+		}
+
+		if filter.Match(script.Tags) {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Oops("Failed to discover the scripts in '"+dir+"'.", err)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// Match tells whether a script with the tags passes the filter tags.
+func (filter SuiteFilter) Match(tags []string) bool {
+	has := map[string]bool{}
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	selected, selecting := false, false
+
+	for _, tag := range filter.Tags {
+		if strings.HasPrefix(tag, "!") {
+			if has[tag[1:]] {
+				return false
+			}
+		} else {
+			selecting = true
+			selected = selected || has[tag]
+		}
+	}
+
+	return selected || !selecting
+}
+
+// matchGlobs tells whether the relative path or it's file name matches any of the patterns.
+func matchGlobs(patterns []string, rel string) bool {
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+
+		if ok, _ := filepath.Match(pattern, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiscoverScripts(T *testing.T) {
+	dir, err := ioutil.TempDir("", "oasis-suite-")
+	assert.Nil(T, err)
+	defer os.RemoveAll(dir)
+
+	os.Mkdir(filepath.Join(dir, "credit"), 0755)
+
+	files := map[string]string{
+		"login.yaml":          "tags: [smoke]\noperations:\n  login:\n    operationId: service.login\n",
+		"credit/approve.yaml": "tags: [credit, slow]\noperations:\n  approve:\n    operationId: service.approve\n",
+		"credit/cancel.yml":   "tags: [credit]\noperations:\n  cancel:\n    operationId: service.cancel\n",
+		"fragments.yaml":      "fragments:\n  login:\n    operations: {}\n",
+		"README.md":           "# Scripts\n",
+	}
+
+	for name, data := range files {
+		assert.Nil(T, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+
+	discover := func(filter SuiteFilter) []string {
+		paths, err := DiscoverScripts(dir, filter)
+		assert.Nil(T, err)
+
+		for i, path := range paths {
+			paths[i], _ = filepath.Rel(dir, path)
+		}
+
+		return paths
+	}
+
+	T.Run("All", func(T *testing.T) {
+		assert.Equal(T, []string{"credit/approve.yaml", "credit/cancel.yml", "login.yaml"}, discover(SuiteFilter{}))
+	})
+
+	T.Run("Globs", func(T *testing.T) {
+		assert.Equal(T, []string{"credit/approve.yaml", "credit/cancel.yml"}, discover(SuiteFilter{Include: []string{"credit/*"}}))
+		assert.Equal(T, []string{"credit/cancel.yml", "login.yaml"}, discover(SuiteFilter{Exclude: []string{"approve.*"}}))
+	})
+
+	T.Run("Tags", func(T *testing.T) {
+		assert.Equal(T, []string{"credit/approve.yaml", "credit/cancel.yml"}, discover(SuiteFilter{Tags: []string{"credit"}}))
+		assert.Equal(T, []string{"credit/cancel.yml", "login.yaml"}, discover(SuiteFilter{Tags: []string{"!slow"}}))
+		assert.Equal(T, []string{"credit/cancel.yml"}, discover(SuiteFilter{Tags: []string{"credit", "!slow"}}))
+	})
+
+	T.Run("Missing directory", func(T *testing.T) {
+		_, err := DiscoverScripts(filepath.Join(dir, "nope"), SuiteFilter{})
+		assert.NotNil(T, err)
+	})
+}
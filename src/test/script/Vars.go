@@ -0,0 +1,134 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/x1n13y84issmd42/oasis/src/errors"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+// VarDepthLimit limits the nesting of variables referencing other variables.
+const VarDepthLimit = 16
+
+// The variables available at run time, or per dataset row,
+// which are interpolated after the script is loaded.
+const (
+	RowVar   = "row"
+	ItemVar  = "item"
+	IndexVar = "index"
+)
+
+var varRx = regexp.MustCompile(`\$\{(env:)?([A-Za-z0-9_.\-]+)\}`)
+
+// Vars are the script variables, available in script string values
+// as ${NAME}. Environment variables are available as ${env:NAME},
+// their values are masked in logs.
+type Vars map[string]string
+
+// Interpolate replaces the ${NAME} & ${env:NAME} references in s
+// with the variable values. References to the deferred variables, and
+// to their fields like ${row.email}, are kept as is, to be interpolated later.
+func (vars Vars) Interpolate(s string, deferred ...string) (string, error) {
+	return vars.interpolate(s, deferred, 0, false)
+}
+
+// Substitute is like Interpolate, but inserts the variable values as is,
+// without interpolating the references in them. It's for the values coming
+// from outside of the script, like the forEach items & dataset rows.
+func (vars Vars) Substitute(s string, deferred ...string) (string, error) {
+	return vars.interpolate(s, deferred, 0, true)
+}
+
+func (vars Vars) interpolate(s string, deferred []string, depth int, literal bool) (string, error) {
+	if depth > VarDepthLimit {
+		return "", errors.Oops(fmt.Sprintf("The variables in '%s' are nested too deep, check them for cycles.", s), nil)
+	}
+
+	var err error
+
+	res := varRx.ReplaceAllStringFunc(s, func(ref string) string {
+		m := varRx.FindStringSubmatch(ref)
+
+		if m[1] != "" {
+			v, ok := os.LookupEnv(m[2])
+			if !ok && err == nil {
+				err = errors.Oops("The environment variable '"+m[2]+"' is not set.", nil)
+			}
+
+			log.Secret(v)
+			return v
+		}
+
+		v, ok := vars[m[2]]
+		if !ok {
+			for _, name := range deferred {
+				if m[2] == name || strings.HasPrefix(m[2], name+".") {
+					return ref
+				}
+			}
+
+			if err == nil {
+				err = errors.Oops("The variable '"+m[2]+"' is not defined in the script.", nil)
+			}
+
+			return ref
+		}
+
+		if literal {
+			return v
+		}
+
+		v, verr := vars.interpolate(v, deferred, depth+1, false)
+		if verr != nil && err == nil {
+			err = verr
+		}
+
+		return v
+	})
+
+	return res, err
+}
+
+// InterpolateData replaces variable references in all the string values
+// of data unmarshalled from YAML, recursively.
+func (vars Vars) InterpolateData(data interface{}, deferred ...string) (interface{}, error) {
+	return vars.interpolateData(data, deferred, false)
+}
+
+// SubstituteData is like InterpolateData, but inserts the variable values as is,
+// see Vars.Substitute().
+func (vars Vars) SubstituteData(data interface{}, deferred ...string) (interface{}, error) {
+	return vars.interpolateData(data, deferred, true)
+}
+
+func (vars Vars) interpolateData(data interface{}, deferred []string, literal bool) (interface{}, error) {
+	switch v := data.(type) {
+	case string:
+		return vars.interpolate(v, deferred, 0, literal)
+
+	case map[interface{}]interface{}:
+		for k, item := range v {
+			item, err := vars.interpolateData(item, deferred, literal)
+			if err != nil {
+				return nil, err
+			}
+
+			v[k] = item
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			item, err := vars.interpolateData(item, deferred, literal)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = item
+		}
+	}
+
+	return data, nil
+}
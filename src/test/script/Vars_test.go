@@ -0,0 +1,77 @@
+package script
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/log"
+)
+
+func Test_Vars(T *testing.T) {
+	vars := Vars{
+		"HOST":    "https://${DOMAIN}/v2",
+		"DOMAIN":  "petstore.swagger.io",
+		"LOOP":    "${LOOP}",
+		"API_KEY": "${env:OASIS_TEST_API_KEY}",
+	}
+
+	T.Run("Interpolate", func(T *testing.T) {
+		v, err := vars.Interpolate("${HOST}/pet/${DOMAIN}")
+		assert.Nil(T, err)
+		assert.Equal(T, "https://petstore.swagger.io/v2/pet/petstore.swagger.io", v)
+	})
+
+	T.Run("Undefined", func(T *testing.T) {
+		_, err := vars.Interpolate("${NOPE}")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Cycle", func(T *testing.T) {
+		_, err := vars.Interpolate("${LOOP}")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("Environment", func(T *testing.T) {
+		os.Setenv("OASIS_TEST_API_KEY", "s3cr3t-k3y")
+		defer os.Unsetenv("OASIS_TEST_API_KEY")
+
+		v, err := vars.Interpolate("key=${API_KEY}")
+		assert.Nil(T, err)
+		assert.Equal(T, "key=s3cr3t-k3y", v)
+		assert.Equal(T, "key="+log.MaskString, log.Mask(v))
+	})
+
+	T.Run("Deferred", func(T *testing.T) {
+		v, err := vars.Interpolate("${DOMAIN}/${row.name}/${item}/${items}", RowVar, ItemVar)
+		assert.NotNil(T, err)
+		assert.Equal(T, "petstore.swagger.io/${row.name}/${item}/${items}", v)
+
+		v, err = vars.Interpolate("${DOMAIN}/${row.name}/${item.id}", RowVar, ItemVar)
+		assert.Nil(T, err)
+		assert.Equal(T, "petstore.swagger.io/${row.name}/${item.id}", v)
+	})
+
+	T.Run("Substitute", func(T *testing.T) {
+		row := Vars{"row.name": "${DOMAIN} ${env:OASIS_TEST_API_KEY} ${nope}"}
+
+		v, err := row.Substitute("name=${row.name}, item=${item}", ItemVar)
+		assert.Nil(T, err)
+		assert.Equal(T, "name=${DOMAIN} ${env:OASIS_TEST_API_KEY} ${nope}, item=${item}", v)
+
+		_, err = row.Substitute("${row.email}")
+		assert.NotNil(T, err)
+	})
+
+	T.Run("InterpolateData", func(T *testing.T) {
+		data := map[interface{}]interface{}{
+			"host": "${HOST}",
+			"list": []interface{}{"${DOMAIN}", 42},
+		}
+
+		_, err := vars.InterpolateData(data)
+		assert.Nil(T, err)
+		assert.Equal(T, "https://petstore.swagger.io/v2", data["host"])
+		assert.Equal(T, []interface{}{"petstore.swagger.io", 42}, data["list"])
+	})
+}
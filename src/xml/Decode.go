@@ -0,0 +1,330 @@
+package xml
+
+import (
+	"bytes"
+	goxml "encoding/xml"
+	"io"
+	"strconv"
+	gostrings "strings"
+)
+
+// Element is a generic XML element tree node.
+type Element struct {
+	Name     goxml.Name
+	Attrs    []goxml.Attr
+	Children []*Element
+	Text     string
+}
+
+// Child returns the first child element with the local name n.
+func (el *Element) Child(n string) *Element {
+	for _, c := range el.Children {
+		if c.Name.Local == n {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// Attr returns the value of the attribute with the local name n.
+func (el *Element) Attr(n string) (string, bool) {
+	for _, a := range el.Attrs {
+		if a.Name.Local == n {
+			return a.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Parse reads an XML document into an Element tree and returns its root.
+func Parse(data []byte) (*Element, error) {
+	decoder := goxml.NewDecoder(bytes.NewReader(data))
+	stack := []*Element{}
+	var root *Element
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case goxml.StartElement:
+			el := &Element{
+				Name: t.Name,
+			}
+
+			for _, a := range t.Attr {
+				// Namespace declarations are not data.
+				if a.Name.Space != "xmlns" && a.Name.Local != "xmlns" {
+					el.Attrs = append(el.Attrs, a)
+				}
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, el)
+			} else if root == nil {
+				root = el
+			}
+
+			stack = append(stack, el)
+
+		case goxml.EndElement:
+			stack = stack[:len(stack)-1]
+
+		case goxml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].Text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return root, nil
+}
+
+// Decode parses an XML document and converts it into generic values
+// (maps, slices, strings, numbers & booleans) the same way a JSON document
+// would be unmarshaled, so the result can be validated against the same
+// JSON schema. The schema (which may be nil) and it's OAS XML objects
+// control element & attribute names, array wrapping and value types.
+func Decode(data []byte, schema Schema) (interface{}, error) {
+	root, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := Decoder{
+		Resolver: Resolver{
+			Root: schema,
+		},
+	}
+
+	return decoder.Value(root, schema), nil
+}
+
+// Decoder converts Element trees into generic values.
+type Decoder struct {
+	Resolver
+}
+
+// Value converts an element into a value according to the schema s.
+func (d Decoder) Value(el *Element, s Schema) interface{} {
+	s = d.Resolve(s)
+	if s == nil {
+		return d.Infer(el)
+	}
+
+	switch Type(s) {
+	case "object":
+		return d.Object(el, s)
+
+	case "array":
+		items := []interface{}{}
+		for _, c := range el.Children {
+			items = append(items, d.Value(c, AsSchema(s["items"])))
+		}
+		return items
+
+	case "":
+		return d.Infer(el)
+	}
+
+	return Cast(gostrings.TrimSpace(el.Text), s)
+}
+
+// Object converts an element into a map according to the object schema s.
+// Attributes & child elements not described by the schema are added
+// as they are, so the additionalProperties rules can catch them.
+func (d Decoder) Object(el *Element, s Schema) map[string]interface{} {
+	obj := map[string]interface{}{}
+	used := map[string]bool{}
+
+	for pn, ps := range Properties(s) {
+		ps = d.Resolve(ps)
+		xo := XMLObject(ps)
+
+		n := pn
+		if xo.Name != "" {
+			n = xo.Name
+		}
+
+		if xo.Attribute {
+			if v, ok := el.Attr(n); ok {
+				obj[pn] = Cast(v, ps)
+				used["@"+n] = true
+			}
+			continue
+		}
+
+		if Type(ps) == "array" {
+			if v, names, ok := d.Array(el, n, pn, ps); ok {
+				obj[pn] = v
+				for _, name := range names {
+					used[name] = true
+				}
+			}
+			continue
+		}
+
+		if c := el.Child(n); c != nil {
+			obj[pn] = d.Value(c, ps)
+			used[n] = true
+		}
+	}
+
+	for _, a := range el.Attrs {
+		if !used["@"+a.Name.Local] {
+			if _, ok := obj[a.Name.Local]; !ok {
+				obj[a.Name.Local] = a.Value
+			}
+		}
+	}
+
+	for n, v := range d.Group(el.Children) {
+		if !used[n] {
+			if _, ok := obj[n]; !ok {
+				obj[n] = v
+			}
+		}
+	}
+
+	return obj
+}
+
+// Array collects array items for the property pn of the parent element.
+// Wrapped arrays have a wrapper element named n which contains the items;
+// unwrapped array items are repeated elements of the parent itself.
+// It returns the items, the names of the consumed child elements
+// and whether anything has been found.
+func (d Decoder) Array(parent *Element, n string, pn string, ps Schema) ([]interface{}, []string, bool) {
+	itemsSchema := d.Resolve(AsSchema(ps["items"]))
+	items := []interface{}{}
+
+	if XMLObject(ps).Wrapped {
+		wrapper := parent.Child(n)
+		if wrapper == nil {
+			return nil, nil, false
+		}
+
+		for _, c := range wrapper.Children {
+			items = append(items, d.Value(c, itemsSchema))
+		}
+
+		return items, []string{n}, true
+	}
+
+	itemName := XMLObject(itemsSchema).Name
+	names := []string{n, pn}
+	if itemName != "" {
+		names = append(names, itemName)
+	}
+
+	for _, c := range parent.Children {
+		for _, name := range names {
+			if c.Name.Local == name {
+				items = append(items, d.Value(c, itemsSchema))
+				break
+			}
+		}
+	}
+
+	return items, names, len(items) > 0
+}
+
+// Infer converts an element into a value without a schema.
+// Text-only elements become strings, elements with children or attributes
+// become maps, and elements which contain two or more children
+// of the same name (and nothing else) become arrays.
+func (d Decoder) Infer(el *Element) interface{} {
+	if len(el.Children) == 0 && len(el.Attrs) == 0 {
+		return gostrings.TrimSpace(el.Text)
+	}
+
+	if len(el.Attrs) == 0 && len(el.Children) > 1 {
+		same := true
+		for _, c := range el.Children {
+			if c.Name.Local != el.Children[0].Name.Local {
+				same = false
+				break
+			}
+		}
+
+		if same {
+			items := []interface{}{}
+			for _, c := range el.Children {
+				items = append(items, d.Infer(c))
+			}
+			return items
+		}
+	}
+
+	obj := map[string]interface{}{}
+
+	for _, a := range el.Attrs {
+		obj[a.Name.Local] = a.Value
+	}
+
+	for n, v := range d.Group(el.Children) {
+		obj[n] = v
+	}
+
+	return obj
+}
+
+// Group infers values of the elements and groups them by name.
+// Repeated names become arrays.
+func (d Decoder) Group(els []*Element) map[string]interface{} {
+	res := map[string]interface{}{}
+	counts := map[string]int{}
+
+	for _, c := range els {
+		counts[c.Name.Local]++
+	}
+
+	for _, c := range els {
+		n := c.Name.Local
+		if counts[n] > 1 {
+			arr, _ := res[n].([]interface{})
+			res[n] = append(arr, d.Infer(c))
+		} else {
+			res[n] = d.Infer(c)
+		}
+	}
+
+	return res
+}
+
+// Cast converts a text value to the native type of the schema s.
+// Values which cannot be converted are returned as strings,
+// so the schema validation reports them properly.
+func Cast(v string, s Schema) interface{} {
+	switch Type(s) {
+	case "integer":
+		if r, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return r
+		}
+
+	case "number":
+		if r, err := strconv.ParseFloat(v, 64); err == nil {
+			return r
+		}
+
+	case "boolean":
+		if r, err := strconv.ParseBool(v); err == nil {
+			return r
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,128 @@
+package xml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/xml"
+)
+
+func petSchema() xml.Schema {
+	return xml.Schema{
+		"$ref": "#/components/schemas/Pet",
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Tag": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":   map[string]interface{}{"type": "integer"},
+						"name": map[string]interface{}{"type": "string"},
+					},
+					"xml": map[string]interface{}{"name": "tag"},
+				},
+				"Pet": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id": map[string]interface{}{
+							"type": "integer",
+							"xml":  map[string]interface{}{"attribute": true},
+						},
+						"name": map[string]interface{}{
+							"type": "string",
+							"xml":  map[string]interface{}{"name": "petName", "prefix": "p", "namespace": "http://pets"},
+						},
+						"available": map[string]interface{}{"type": "boolean"},
+						"photoUrls": map[string]interface{}{
+							"type":  "array",
+							"xml":   map[string]interface{}{"name": "photoUrls", "wrapped": true},
+							"items": map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "photoUrl"}},
+						},
+						"tags": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"$ref": "#/components/schemas/Tag"},
+						},
+					},
+					"xml": map[string]interface{}{"name": "Pet"},
+				},
+			},
+		},
+	}
+}
+
+func Test_Decode(T *testing.T) {
+	T.Run("Schema", func(T *testing.T) {
+		data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<Pet id="10" xmlns:p="http://pets">
+			<p:petName>doggie</p:petName>
+			<available>true</available>
+			<photoUrls>
+				<photoUrl>a.png</photoUrl>
+				<photoUrl>b.png</photoUrl>
+			</photoUrls>
+			<tag><id>1</id><name>good</name></tag>
+			<tag><id>2</id><name>boy</name></tag>
+			<extra>surprise</extra>
+		</Pet>`)
+
+		expected := map[string]interface{}{
+			"id":        int64(10),
+			"name":      "doggie",
+			"available": true,
+			"photoUrls": []interface{}{"a.png", "b.png"},
+			"tags": []interface{}{
+				map[string]interface{}{"id": int64(1), "name": "good"},
+				map[string]interface{}{"id": int64(2), "name": "boy"},
+			},
+			"extra": "surprise",
+		}
+
+		actual, err := xml.Decode(data, petSchema())
+
+		assert.Nil(T, err)
+		assert.Equal(T, expected, actual)
+	})
+
+	T.Run("Uncastable", func(T *testing.T) {
+		actual, err := xml.Decode([]byte(`<Pet id="ten"/>`), petSchema())
+
+		assert.Nil(T, err)
+		assert.Equal(T, map[string]interface{}{"id": "ten"}, actual)
+	})
+
+	T.Run("Root array", func(T *testing.T) {
+		schema := xml.Schema{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "integer"},
+		}
+
+		actual, err := xml.Decode([]byte(`<ids><id>1</id><id>2</id></ids>`), schema)
+
+		assert.Nil(T, err)
+		assert.Equal(T, []interface{}{int64(1), int64(2)}, actual)
+	})
+
+	T.Run("No schema", func(T *testing.T) {
+		data := []byte(`<pets>
+			<Pet id="1"><name>doggie</name></Pet>
+			<Pet id="2"><name>kitty</name><tag>a</tag><tag>b</tag></Pet>
+		</pets>`)
+
+		expected := []interface{}{
+			map[string]interface{}{"id": "1", "name": "doggie"},
+			map[string]interface{}{"id": "2", "name": "kitty", "tag": []interface{}{"a", "b"}},
+		}
+
+		actual, err := xml.Decode(data, nil)
+
+		assert.Nil(T, err)
+		assert.Equal(T, expected, actual)
+	})
+
+	T.Run("Malformed", func(T *testing.T) {
+		_, err := xml.Decode([]byte(`<Pet><name>doggie</Pet>`), nil)
+		assert.NotNil(T, err)
+
+		_, err = xml.Decode([]byte(``), nil)
+		assert.NotNil(T, err)
+	})
+}
@@ -0,0 +1,207 @@
+package xml
+
+import (
+	goxml "encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	gostrings "strings"
+)
+
+// Encode encodes a generic value (maps, slices & scalars) as an XML document.
+// The schema (which may be nil) and it's OAS XML objects control element
+// & attribute names, namespaces, prefixes and array wrapping.
+// The root element is named after the schema's XML object name,
+// the referenced component name or "root", in that order.
+func Encode(v interface{}, schema Schema) ([]byte, error) {
+	encoder := Encoder{
+		Resolver: Resolver{
+			Root: schema,
+		},
+		Builder: &gostrings.Builder{},
+	}
+
+	name := XMLObject(encoder.Resolve(schema)).Name
+	if name == "" {
+		name = RefName(schema)
+	}
+	if name == "" {
+		name = "root"
+	}
+
+	encoder.Builder.WriteString(goxml.Header)
+
+	err := encoder.Element(name, v, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(encoder.Builder.String()), nil
+}
+
+// Encoder writes generic values as XML.
+type Encoder struct {
+	Resolver
+	Builder *gostrings.Builder
+}
+
+// Element writes v as an element named n (unless the schema says otherwise).
+func (e Encoder) Element(n string, v interface{}, s Schema) error {
+	s = e.Resolve(s)
+	xo := XMLObject(s)
+	if xo.Name != "" {
+		n = xo.Name
+	}
+
+	tag := e.Tag(n, xo)
+	attrs := e.NamespaceAttr(xo)
+
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		props := Properties(s)
+		children := []string{}
+
+		keys := []string{}
+		for k := range tv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			ps := e.Resolve(props[k])
+			pxo := XMLObject(ps)
+
+			if pxo.Attribute {
+				an := k
+				if pxo.Name != "" {
+					an = pxo.Name
+				}
+
+				attrs += " " + e.Tag(an, pxo) + "=\"" + e.Escape(Scalar(tv[k])) + "\""
+				continue
+			}
+
+			children = append(children, k)
+		}
+
+		e.Builder.WriteString("<" + tag + attrs + ">")
+
+		for _, k := range children {
+			err := e.Property(k, tv[k], props[k])
+			if err != nil {
+				return err
+			}
+		}
+
+		e.Builder.WriteString("</" + tag + ">")
+
+	case []interface{}:
+		items := e.Resolve(AsSchema(s["items"]))
+		itemName := XMLObject(items).Name
+		if itemName == "" {
+			itemName = "item"
+		}
+
+		e.Builder.WriteString("<" + tag + attrs + ">")
+
+		for _, item := range tv {
+			err := e.Element(itemName, item, items)
+			if err != nil {
+				return err
+			}
+		}
+
+		e.Builder.WriteString("</" + tag + ">")
+
+	default:
+		e.Builder.WriteString("<" + tag + attrs + ">" + e.Escape(Scalar(v)) + "</" + tag + ">")
+	}
+
+	return nil
+}
+
+// Property writes an object property pn. Arrays are written either
+// wrapped in an element, or as repeated elements, depending on the XML object.
+func (e Encoder) Property(pn string, v interface{}, ps Schema) error {
+	ps = e.Resolve(ps)
+	arr, isArray := v.([]interface{})
+
+	if !isArray {
+		return e.Element(pn, v, ps)
+	}
+
+	xo := XMLObject(ps)
+	n := pn
+	if xo.Name != "" {
+		n = xo.Name
+	}
+
+	items := e.Resolve(AsSchema(ps["items"]))
+	itemName := XMLObject(items).Name
+	if itemName == "" {
+		itemName = n
+	}
+
+	if xo.Wrapped {
+		tag := e.Tag(n, xo)
+		e.Builder.WriteString("<" + tag + e.NamespaceAttr(xo) + ">")
+		defer e.Builder.WriteString("</" + tag + ">")
+	}
+
+	for _, item := range arr {
+		err := e.Element(itemName, item, items)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tag returns a possibly prefixed element name.
+func (e Encoder) Tag(n string, xo Object) string {
+	if xo.Prefix != "" {
+		return xo.Prefix + ":" + n
+	}
+
+	return n
+}
+
+// NamespaceAttr returns a namespace declaration attribute, if needed.
+func (e Encoder) NamespaceAttr(xo Object) string {
+	if xo.Namespace == "" {
+		return ""
+	}
+
+	if xo.Prefix != "" {
+		return " xmlns:" + xo.Prefix + "=\"" + e.Escape(xo.Namespace) + "\""
+	}
+
+	return " xmlns=\"" + e.Escape(xo.Namespace) + "\""
+}
+
+// Escape escapes special XML characters.
+func (e Encoder) Escape(v string) string {
+	b := &gostrings.Builder{}
+	goxml.EscapeText(b, []byte(v))
+	return b.String()
+}
+
+// Scalar formats a scalar value as a string.
+func Scalar(v interface{}) string {
+	switch tv := v.(type) {
+	case nil:
+		return ""
+
+	case string:
+		return tv
+
+	case float64:
+		return strconv.FormatFloat(tv, 'f', -1, 64)
+
+	case bool:
+		return strconv.FormatBool(tv)
+	}
+
+	return fmt.Sprint(v)
+}
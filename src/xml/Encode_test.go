@@ -0,0 +1,64 @@
+package xml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/x1n13y84issmd42/oasis/src/xml"
+)
+
+func Test_Encode(T *testing.T) {
+	T.Run("Schema", func(T *testing.T) {
+		data := map[string]interface{}{
+			"id":        10,
+			"name":      "dog & cat",
+			"photoUrls": []interface{}{"a.png", "b.png"},
+			"tags": []interface{}{
+				map[string]interface{}{"id": 1, "name": "good"},
+			},
+		}
+
+		expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<Pet id="10">` +
+			`<p:petName xmlns:p="http://pets">dog &amp; cat</p:petName>` +
+			`<photoUrls><photoUrl>a.png</photoUrl><photoUrl>b.png</photoUrl></photoUrls>` +
+			`<tag><id>1</id><name>good</name></tag>` +
+			`</Pet>`
+
+		actual, err := xml.Encode(data, petSchema())
+
+		assert.Nil(T, err)
+		assert.Equal(T, expected, string(actual))
+	})
+
+	T.Run("Round trip", func(T *testing.T) {
+		data := map[string]interface{}{
+			"id":        int64(10),
+			"name":      "doggie",
+			"available": false,
+			"photoUrls": []interface{}{"a.png"},
+		}
+
+		encoded, err := xml.Encode(data, petSchema())
+		assert.Nil(T, err)
+
+		actual, err := xml.Decode(encoded, petSchema())
+		assert.Nil(T, err)
+		assert.Equal(T, data, actual)
+	})
+
+	T.Run("No schema", func(T *testing.T) {
+		data := map[string]interface{}{
+			"name": "doggie",
+			"ids":  []interface{}{1, 2.5},
+		}
+
+		expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<root><ids>1</ids><ids>2.5</ids><name>doggie</name></root>`
+
+		actual, err := xml.Encode(data, nil)
+
+		assert.Nil(T, err)
+		assert.Equal(T, expected, string(actual))
+	})
+}
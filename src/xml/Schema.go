@@ -0,0 +1,140 @@
+package xml
+
+import (
+	"reflect"
+	gostrings "strings"
+)
+
+// Schema is a JSON schema node as it comes from api.Schema.
+// The root node carries the spec components used to resolve $refs.
+type Schema = map[string]interface{}
+
+// Object is an OAS XML object, which controls how a schema
+// is represented in XML documents.
+type Object struct {
+	Name      string
+	Namespace string
+	Prefix    string
+	Attribute bool
+	Wrapped   bool
+}
+
+// AsSchema converts v to a Schema when it is a map with string keys.
+// Nested schemas may come either as plain maps (when unmarshaled from JSON)
+// or as named map types (like api.JSONSchema, when built in code).
+func AsSchema(v interface{}) Schema {
+	if v == nil {
+		return nil
+	}
+
+	if s, ok := v.(Schema); ok {
+		return s
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	s := Schema{}
+	for _, k := range rv.MapKeys() {
+		s[k.String()] = rv.MapIndex(k).Interface()
+	}
+
+	return s
+}
+
+// Resolver resolves $refs in schema nodes against the root schema.
+type Resolver struct {
+	Root Schema
+}
+
+// Resolve follows the "$ref" of s, if any, and returns the referenced schema.
+// Only local references like "#/components/schemas/Pet" are supported.
+func (r Resolver) Resolve(s Schema) Schema {
+	for i := 0; s != nil && i < 32; i++ {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s
+		}
+
+		s = r.Lookup(ref)
+	}
+
+	return s
+}
+
+// Lookup finds a schema node by a local JSON pointer.
+func (r Resolver) Lookup(ref string) Schema {
+	if !gostrings.HasPrefix(ref, "#/") {
+		return nil
+	}
+
+	var node interface{} = r.Root
+	for _, segment := range gostrings.Split(ref[2:], "/") {
+		m := AsSchema(node)
+		if m == nil {
+			return nil
+		}
+
+		segment = gostrings.ReplaceAll(segment, "~1", "/")
+		segment = gostrings.ReplaceAll(segment, "~0", "~")
+		node = m[segment]
+	}
+
+	return AsSchema(node)
+}
+
+// RefName returns the last segment of the $ref of s,
+// so "#/components/schemas/Pet" gives "Pet".
+func RefName(s Schema) string {
+	if ref, ok := s["$ref"].(string); ok {
+		return ref[gostrings.LastIndex(ref, "/")+1:]
+	}
+
+	return ""
+}
+
+// XMLObject reads the OAS XML object from s.
+func XMLObject(s Schema) Object {
+	obj := Object{}
+
+	xo := AsSchema(s["xml"])
+	if xo == nil {
+		return obj
+	}
+
+	obj.Name, _ = xo["name"].(string)
+	obj.Namespace, _ = xo["namespace"].(string)
+	obj.Prefix, _ = xo["prefix"].(string)
+	obj.Attribute, _ = xo["attribute"].(bool)
+	obj.Wrapped, _ = xo["wrapped"].(bool)
+
+	return obj
+}
+
+// Type returns the schema type. Schemas with properties but without
+// an explicit type are considered objects.
+func Type(s Schema) string {
+	if t, ok := s["type"].(string); ok {
+		return t
+	}
+
+	if s["properties"] != nil {
+		return "object"
+	}
+
+	return ""
+}
+
+// Properties returns the schema properties.
+func Properties(s Schema) map[string]Schema {
+	res := map[string]Schema{}
+
+	props := AsSchema(s["properties"])
+	for pn, ps := range props {
+		res[pn] = AsSchema(ps)
+	}
+
+	return res
+}